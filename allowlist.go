@@ -0,0 +1,203 @@
+package builq
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Allowlist of reviewed query shapes, see [Builder.Fingerprint].
+//
+// In strict mode (default) [Builder.Build] returns [*NotAllowedError]
+// for a query which shape isn't in the allowlist.
+// In record mode (see [Allowlist.Record]) new shapes are written for a review and allowed.
+//
+// Note that the shape keeps `%s` and `%e` as opaque tokens,
+// text written through them isn't checked by the allowlist.
+//
+// Allowlist is safe for concurrent use.
+type Allowlist struct {
+	mu     sync.RWMutex
+	shapes map[uint64]string
+	record io.Writer
+}
+
+// NotAllowedError is returned by [Builder.Build] when the query shape isn't in the [Allowlist].
+type NotAllowedError struct {
+	Shape string
+	Hash  uint64
+}
+
+func (e *NotAllowedError) Error() string {
+	return fmt.Sprintf("query shape %016x is not allowed: %s", e.Hash, e.Shape)
+}
+
+var defaultAllowlist atomic.Pointer[Allowlist]
+
+// SetAllowlist sets the allowlist for all builders which don't have their own.
+// Pass nil to disable.
+func SetAllowlist(a *Allowlist) {
+	defaultAllowlist.Store(a)
+}
+
+// Allowlist sets the allowlist for the builder, see [Allowlist].
+func (b *Builder) Allowlist(a *Allowlist) *Builder {
+	b.allowlist = a
+	return b
+}
+
+// NewAllowlist returns a new allowlist with the given shapes.
+func NewAllowlist(shapes ...string) *Allowlist {
+	a := &Allowlist{shapes: make(map[uint64]string, len(shapes))}
+	for _, shape := range shapes {
+		a.Add(shape)
+	}
+	return a
+}
+
+// LoadAllowlist reads an allowlist from a file, see [ReadAllowlist] for the format.
+func LoadAllowlist(filename string) (*Allowlist, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadAllowlist(f)
+}
+
+// ReadAllowlist reads an allowlist from r.
+// Each line is a shape, lines with newlines inside are Go-quoted.
+// Empty lines and lines starting with `#` are skipped.
+func ReadAllowlist(r io.Reader) (*Allowlist, error) {
+	a := NewAllowlist()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		shape := strings.TrimSpace(scanner.Text())
+		switch {
+		case shape == "" || shape[0] == '#':
+			continue
+		case shape[0] == '"':
+			unquoted, err := strconv.Unquote(shape)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			shape = unquoted
+		}
+		a.Add(shape)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Add a shape to the allowlist.
+func (a *Allowlist) Add(shape string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.shapes[shapeHash(shape)] = shape
+}
+
+// AddBuilder adds the builder's query shape to the allowlist.
+// Useful to generate an allowlist in tests.
+func (a *Allowlist) AddBuilder(b *Builder) error {
+	shape, _, err := b.Fingerprint()
+	if err != nil {
+		return err
+	}
+	a.Add(shape)
+	return nil
+}
+
+// Record enables record mode: new shapes are written to w and allowed.
+// Pass nil to get back to strict mode.
+func (a *Allowlist) Record(w io.Writer) *Allowlist {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.record = w
+	return a
+}
+
+// Allowed reports whether the shape is in the allowlist.
+func (a *Allowlist) Allowed(shape string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.shapes[shapeHash(shape)] == shape
+}
+
+// WriteTo writes sorted shapes in a format accepted by [ReadAllowlist].
+func (a *Allowlist) WriteTo(w io.Writer) (int64, error) {
+	a.mu.RLock()
+	shapes := make([]string, 0, len(a.shapes))
+	for _, shape := range a.shapes {
+		shapes = append(shapes, shape)
+	}
+	a.mu.RUnlock()
+	sort.Strings(shapes)
+
+	var total int64
+	for _, shape := range shapes {
+		n, err := io.WriteString(w, formatShapeLine(shape))
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (a *Allowlist) check(shape string, hash uint64) error {
+	a.mu.RLock()
+	allowed := a.shapes[hash] == shape
+	record := a.record
+	a.mu.RUnlock()
+
+	switch {
+	case allowed:
+		return nil
+	case record == nil:
+		return &NotAllowedError{Shape: shape, Hash: hash}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.shapes[hash] == shape {
+		return nil
+	}
+	if _, err := io.WriteString(record, formatShapeLine(shape)); err != nil {
+		return err
+	}
+	a.shapes[hash] = shape
+	return nil
+}
+
+func (b *Builder) checkAllowlist() error {
+	a := b.allowlist
+	if a == nil {
+		a = defaultAllowlist.Load()
+	}
+	if a == nil {
+		return nil
+	}
+
+	shape, hash, err := b.Fingerprint()
+	if err != nil {
+		return err
+	}
+	return a.check(shape, hash)
+}
+
+func formatShapeLine(shape string) string {
+	if strings.ContainsAny(shape, "\n\r") || strings.HasPrefix(shape, "#") || strings.HasPrefix(shape, `"`) {
+		shape = strconv.Quote(shape)
+	}
+	return shape + "\n"
+}
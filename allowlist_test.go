@@ -0,0 +1,105 @@
+package builq
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAllowlist(t *testing.T) {
+	newBuilder := func(ids []int) *Builder {
+		var b Builder
+		b.Addf("SELECT * FROM users WHERE id IN (%+$)", ids)
+		return &b
+	}
+
+	a := NewAllowlist()
+	if err := a.AddBuilder(newBuilder([]int{1})); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := newBuilder([]int{1, 2, 3}).Allowlist(a).Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	var b Builder
+	b.Allowlist(a)
+	b.Addf("DELETE FROM users")
+	_, _, err := b.Build()
+
+	var notAllowed *NotAllowedError
+	if !errors.As(err, &notAllowed) {
+		t.Fatalf("want NotAllowedError, have %v", err)
+	}
+	if notAllowed.Shape != "DELETE FROM users" {
+		t.Fatalf("have %q", notAllowed.Shape)
+	}
+	if b.DebugBuild() != "DELETE FROM users" {
+		t.Fatal("debug build must not be checked")
+	}
+
+	var record bytes.Buffer
+	a.Record(&record)
+	for i := 0; i < 2; i++ {
+		if _, _, err := b.Build(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if have := record.String(); have != "DELETE FROM users\n" {
+		t.Fatalf("have %q", have)
+	}
+
+	a.Record(nil)
+	if _, _, err := b.Build(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAllowlistReadWrite(t *testing.T) {
+	a := NewAllowlist("SELECT 1", "SELECT 'a\nb'", "# not a comment")
+
+	var buf bytes.Buffer
+	if _, err := a.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	const want = "\"# not a comment\"\n\"SELECT 'a\\nb'\"\nSELECT 1\n"
+	if have := buf.String(); have != want {
+		t.Fatalf("\nhave: %q\nwant: %q", have, want)
+	}
+
+	b, err := ReadAllowlist(strings.NewReader("# comment\n\n" + buf.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, shape := range []string{"SELECT 1", "SELECT 'a\nb'", "# not a comment"} {
+		if !b.Allowed(shape) {
+			t.Errorf("%q must be allowed", shape)
+		}
+	}
+	if b.Allowed("SELECT 2") {
+		t.Error("must not be allowed")
+	}
+
+	if _, err := ReadAllowlist(strings.NewReader(`"SELECT`)); err == nil {
+		t.Fatal("want error")
+	}
+}
+
+func TestSetAllowlist(t *testing.T) {
+	SetAllowlist(NewAllowlist("SELECT 1"))
+	defer SetAllowlist(nil)
+
+	q := New()
+	q("SELECT 1")
+	if _, _, err := q.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	q = New()
+	q("SELECT 2")
+	var notAllowed *NotAllowedError
+	if _, _, err := q.Build(); !errors.As(err, &notAllowed) {
+		t.Fatalf("want NotAllowedError, have %v", err)
+	}
+}
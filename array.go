@@ -0,0 +1,126 @@
+package builq
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cristalhq/builq/internal/values"
+)
+
+// ArrayEncoder converts a slice passed to `%A` verb into a single driver argument.
+// See [Builder.ArrayEncoder].
+type ArrayEncoder func(slice any) (any, error)
+
+// ArrayEncoder sets the encoder for `%A` verb, like `pq.Array` for lib/pq.
+// For pgx return the slice as is, it supports Go slices natively.
+// By default the slice is encoded as a [driver.Valuer] with PostgreSQL array literal `{1,2,3}`.
+func (b *Builder) ArrayEncoder(enc ArrayEncoder) *Builder {
+	b.arrayEncoder = enc
+	return b
+}
+
+func (b *Builder) writeArray(sb *strings.Builder, resArgs *[]any, verb byte, arg any) error {
+	args, err := b.asSlice(arg)
+	if err != nil {
+		return err
+	}
+
+	if b.debug {
+		sb.WriteString("ARRAY[")
+		for i, arg := range args {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			b.writeDebug(sb, arg)
+		}
+		sb.WriteByte(']')
+		return nil
+	}
+
+	// ClickHouse has native arrays, see [Builder.writeTyped].
+	if verb == '{' {
+		return b.writeArg(sb, resArgs, verb, arg)
+	}
+
+	enc := b.arrayEncoder
+	if enc == nil {
+		enc = encodeArray
+	}
+	value, err := enc(arg)
+	if err != nil {
+		return err
+	}
+	return b.writeArg(sb, resArgs, verb, value)
+}
+
+// arrayValue is a PostgreSQL array literal.
+type arrayValue string
+
+// Value implements the [driver.Valuer] interface.
+func (a arrayValue) Value() (driver.Value, error) {
+	return string(a), nil
+}
+
+// encodeArray is the default [ArrayEncoder].
+func encodeArray(slice any) (any, error) {
+	var sb strings.Builder
+	if err := writeArrayLiteral(&sb, reflect.ValueOf(slice)); err != nil {
+		return nil, err
+	}
+	return arrayValue(sb.String()), nil
+}
+
+func writeArrayLiteral(sb *strings.Builder, v reflect.Value) error {
+	sb.WriteByte('{')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if err := writeArrayElem(sb, v.Index(i)); err != nil {
+			return err
+		}
+	}
+	sb.WriteByte('}')
+	return nil
+}
+
+func writeArrayElem(sb *strings.Builder, v reflect.Value) error {
+	v, isNull, err := values.Resolve(v)
+	switch {
+	case err != nil:
+		return err
+	case isNull:
+		sb.WriteString("NULL")
+		return nil
+	case v.Kind() == reflect.Array || (v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8):
+		return writeArrayLiteral(sb, v)
+	}
+
+	s, bare, err := values.Text(v)
+	switch {
+	case errors.Is(err, values.ErrUnsupported):
+		return fmt.Errorf("%w: %s", errUnsupportedArrayElem, v.Type())
+	case err != nil:
+		return err
+	case bare:
+		sb.WriteString(s)
+	default:
+		writeArrayString(sb, s)
+	}
+	return nil
+}
+
+// writeArrayString writes a quoted array element, `"` and `\` are escaped.
+func writeArrayString(sb *strings.Builder, s string) {
+	sb.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(s[i])
+	}
+	sb.WriteByte('"')
+}
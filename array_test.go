@@ -0,0 +1,93 @@
+package builq
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestArray(t *testing.T) {
+	var b Builder
+	b.Addf("SELECT * FROM users WHERE id = ANY(%A$) AND name <> ALL(%A$) AND org = %$", []int64{1, 2, 3}, []string{"a b", `q"\`}, 42)
+
+	query, args, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT * FROM users WHERE id = ANY($1) AND name <> ALL($2) AND org = $3"
+	if query != wantQuery {
+		t.Fatalf("\nhave: %s\nwant: %s", query, wantQuery)
+	}
+	wantArgs := []any{arrayValue("{1,2,3}"), arrayValue(`{"a b","q\"\\"}`), 42}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("\nhave: %v\nwant: %v", args, wantArgs)
+	}
+
+	wantDebug := `SELECT * FROM users WHERE id = ANY(ARRAY[1, 2, 3]) AND name <> ALL(ARRAY['a b', 'q"\']) AND org = 42`
+	if debug := b.DebugBuild(); debug != wantDebug {
+		t.Fatalf("\nhave: %s\nwant: %s", debug, wantDebug)
+	}
+}
+
+func TestArrayEncoder(t *testing.T) {
+	var b Builder
+	b.ArrayEncoder(func(slice any) (any, error) {
+		return slice, nil
+	})
+	b.Addf("WHERE id = ANY(%A$)", []int{1, 2})
+
+	_, args, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(args, []any{[]int{1, 2}}) {
+		t.Fatalf("have %v", args)
+	}
+}
+
+func TestEncodeArray(t *testing.T) {
+	test := func(slice any, want string) {
+		t.Helper()
+		v, err := encodeArray(slice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		have, _ := v.(driver.Valuer).Value()
+		if have != want {
+			t.Errorf("\nhave: %s\nwant: %s", have, want)
+		}
+	}
+
+	one := 1
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	test([]int{}, "{}")
+	test([]bool{true, false}, "{t,f}")
+	test([]float64{1.5, -2}, "{1.5,-2}")
+	test([]*int{&one, nil}, "{1,NULL}")
+	test([]any{"x", nil, 2}, `{"x",NULL,2}`)
+	test([][]int{{1, 2}, {3, 4}}, "{{1,2},{3,4}}")
+	test([][]byte{{0xde, 0xad}}, `{"\\xdead"}`)
+	test([]time.Time{ts}, `{"2024-01-02 03:04:05+00:00"}`)
+	test([][]byte{nil}, "{NULL}")
+	test([]float64{math.NaN(), math.Inf(-1)}, "{NaN,-Infinity}")
+	test([]sql.NullString{{String: "a", Valid: true}, {}}, `{"a",NULL}`)
+}
+
+func TestArrayErrors(t *testing.T) {
+	var b Builder
+	b.Addf("WHERE id = ANY(%A$)", 1)
+	if _, _, err := b.Build(); !errors.Is(err, errNonSliceArgument) {
+		t.Fatalf("have %v, want %v", err, errNonSliceArgument)
+	}
+
+	var bad Builder
+	bad.Addf("WHERE id = ANY(%A$)", []struct{}{{}})
+	if _, _, err := bad.Build(); !errors.Is(err, errUnsupportedArrayElem) {
+		t.Fatalf("have %v, want %v", err, errUnsupportedArrayElem)
+	}
+}
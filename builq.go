@@ -29,9 +29,56 @@ type Builder struct {
 	placeholder byte // a placeholder used to build the query.
 	sep         byte // a separator between Addf calls.
 	debug       bool // is it DebugBuild call to fill with args.
+
+	literals  LiteralMode // how to handle verbs inside literals and comments.
+	normalize bool        // collapse whitespace and line comments in formats.
+	namedArgs bool        // wrap `%@` arguments in sql.NamedArg.
+
+	emptySlices  EmptySliceMode // how to handle empty slices for `%+` verb.
+	arrayEncoder ArrayEncoder   // encodes slices for `%A` verb.
+	padding      Padding        // pads slices for `%+` verb.
+	stats        Stats          // stats of the last build.
+	sourceRead   bool           // RowSource of the batch is read by Chunks.
+	err          error          // an error of the added input, returned on build.
+	shape        *fingerprint   // cached Fingerprint, reset when parts or literal mode change.
+
+	tags         map[string]string // sqlcommenter tags.
+	tagPlacement TagPlacement      // where the tags comment goes.
+	allowlist    *Allowlist        // allowed query shapes.
 }
 
+// LiteralMode defines how `%` inside SQL literals and comments is handled.
+type LiteralMode byte
+
+const (
+	// LiteralVerbs formats verbs everywhere, even in literals like '%s'. This is the default.
+	LiteralVerbs LiteralMode = iota
+
+	// LiteralText writes literals and comments as is, `%` inside them isn't a verb.
+	LiteralText
+
+	// LiteralError returns an error when `%` is found inside a literal or a comment.
+	LiteralError
+)
+
+// EmptySliceMode defines how an empty slice for `%+` is handled, `IN ()` is a syntax error in SQL.
+type EmptySliceMode byte
+
+const (
+	// EmptySliceError returns an error on build. This is the default.
+	EmptySliceError EmptySliceMode = iota
+
+	// EmptySliceNull writes NULL, so `IN (NULL)` matches nothing.
+	// Note that `NOT IN (NULL)` matches nothing too.
+	EmptySliceNull
+
+	// EmptySliceFalse writes a subquery without rows, like `SELECT NULL WHERE FALSE`,
+	// a dialect is chosen by the placeholder. Both `IN` and `NOT IN` work as expected.
+	EmptySliceFalse
+)
+
 // OnelineBuilder behaves like Builder but result is 1 line.
+// Formats are always normalized, see [Builder.Normalize].
 type OnelineBuilder struct {
 	Builder
 }
@@ -72,6 +119,7 @@ func (b *OnelineBuilder) Addf(format constString, args ...any) *Builder {
 	if b.sep == 0 {
 		b.sep = ' '
 	}
+	b.normalize = true
 	return b.addf(format, args...)
 }
 
@@ -84,7 +132,40 @@ func (b *Builder) Addf(format constString, args ...any) *Builder {
 	return b.addf(format, args...)
 }
 
+// Literals sets how `%` inside string literals, quoted identifiers,
+// dollar-quoted bodies and comments is handled. See [LiteralMode].
+func (b *Builder) Literals(mode LiteralMode) *Builder {
+	b.literals = mode
+	b.shape = nil
+	return b
+}
+
+// EmptySlices sets how an empty slice for `%+` is handled. See [EmptySliceMode].
+func (b *Builder) EmptySlices(mode EmptySliceMode) *Builder {
+	b.emptySlices = mode
+	return b
+}
+
+// Normalize enables normalization of formats: whitespace and newlines outside
+// literals are collapsed into a single space and `--` comments are converted to `/* */`.
+// The result query is semantically the same but is safe to be written in one line.
+func (b *Builder) Normalize(enabled bool) *Builder {
+	b.normalize = enabled
+	return b
+}
+
+// NamedArgs enables wrapping of `%@` arguments in [sql.NamedArg] with names
+// matching placeholders: `@p1` gets `sql.Named("p1", v)` and so on.
+// Some MSSQL drivers bind `@pN` placeholders only by name.
+//
+// An explicit [sql.NamedArg] argument always produces `@name` with the argument as is.
+func (b *Builder) NamedArgs(enabled bool) *Builder {
+	b.namedArgs = enabled
+	return b
+}
+
 // Build the query and arguments.
+// Build can be called many times, placeholders are numbered from 1 on every call.
 func (b *Builder) Build() (query string, args []any, err error) {
 	return b.build()
 }
@@ -105,10 +186,26 @@ func (b *Builder) addf(format constString, args ...any) *Builder {
 	}
 	b.parts = append(b.parts, string(format))
 	b.args = append(b.args, args)
+	b.shape = nil
 	return b
 }
 
 func (b *Builder) build() (string, []any, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	// every build is independent, so retries and re-runs send the same query.
+	b.counter = 0
+	b.placeholder = 0
+
+	if !b.debug {
+		b.stats = Stats{}
+		if err := b.checkAllowlist(); err != nil {
+			return "", nil, err
+		}
+	}
+
 	var query strings.Builder
 	// TODO: better default (sum of parts + est len of indexes)
 	query.Grow(100)
@@ -119,13 +216,27 @@ func (b *Builder) build() (string, []any, error) {
 		format := b.parts[i]
 		args := b.args[i]
 
+		if b.normalize {
+			format = normalizeFormat(format)
+		}
+
 		if err := b.write(&query, &resArgs, format, args...); err != nil {
 			return "", nil, err
 		}
 	}
 
+	if !b.debug {
+		b.stats.Args = len(resArgs)
+	}
+
 	// drop last separators for clarity.
 	q := strings.TrimRight(query.String(), string(b.sep))
+	if len(b.tags) > 0 {
+		var err error
+		if q, err = b.appendTags(q); err != nil {
+			return "", nil, err
+		}
+	}
 	return q, resArgs, nil
 }
 
@@ -148,9 +259,63 @@ var (
 	// errMixedPlaceholders when $ AND ? are mixed in 1 query.
 	errMixedPlaceholders = errors.New("mixed placeholders in a single query")
 
-	// errNonSliceArgument when a non-slice argument passed to placeholder with `+` or `#`.
+	// errNonSliceArgument when a non-slice argument passed to placeholder with `+`, `#` or `A`.
 	errNonSliceArgument = errors.New("non-slice arguments with slice modifiers")
 
+	// errEmptySlice when an empty slice is passed to `%+` with [EmptySliceError] mode.
+	errEmptySlice = errors.New("empty slice")
+
+	// errChunkBatch when [Builder.Chunks] is called for a query without exactly one `%#` verb.
+	errChunkBatch = errors.New("chunks require exactly one batch verb")
+
+	// errChunkLimit when a single row doesn't fit the parameter limit of [Builder.Chunks].
+	errChunkLimit = errors.New("row doesn't fit the parameter limit")
+
+	// errMismatchedRows when rows for `%U` have different number of columns.
+	errMismatchedRows = errors.New("mismatched number of columns")
+
+	// errRowSource when [RowSource] is passed to `%#` outside of [Builder.Chunks].
+	errRowSource = errors.New("row source can be used only with Chunks")
+
+	// errRowSourceRead when [Builder.Chunks] is called again for a [RowSource].
+	errRowSourceRead = errors.New("row source is already read")
+
+	// errNilTemplate when a nil [Template] is added.
+	errNilTemplate = errors.New("nil template")
+
+	// errNoTemplate when [Templates] has no template with the name.
+	errNoTemplate = errors.New("no template")
+
+	// errNonExprArgument when an argument passed to `%e` doesn't implement [Expr].
+	errNonExprArgument = errors.New("argument doesn't implement Expr")
+
+	// errUnsupportedStyle when an unknown [Style] is passed.
+	errUnsupportedStyle = errors.New("unsupported placeholder style")
+
+	// errUnterminatedLiteral when a string, identifier or comment isn't closed.
+	errUnterminatedLiteral = errors.New("unterminated literal or comment")
+
+	// errAmbiguousPlaceholder when placeholders cannot be converted safely.
+	errAmbiguousPlaceholder = errors.New("ambiguous placeholder")
+
+	// errVerbInLiteral when a verb is found inside a literal or a comment with [LiteralError] mode.
+	errVerbInLiteral = errors.New("verb inside string literal or comment")
+
+	// errTagsAfterLineComment when a one-line query ends with a `--` comment and tags must follow it.
+	errTagsAfterLineComment = errors.New("tags comment cannot follow a line comment in a one-line query")
+
+	// errInvalidArgName when [sql.NamedArg] name isn't a valid identifier.
+	errInvalidArgName = errors.New("invalid argument name")
+
+	// errInvalidTypeName when [TypedArg] type isn't a valid ClickHouse type.
+	errInvalidTypeName = errors.New("invalid type name")
+
+	// errUnknownType when a ClickHouse type cannot be inferred from the argument.
+	errUnknownType = errors.New("cannot infer type of argument")
+
+	// errUnsupportedArrayElem when a slice element cannot be encoded as a PostgreSQL array element.
+	errUnsupportedArrayElem = errors.New("unsupported array element")
+
 	// errNonNumericArg expected number for %d but got something else.
 	errNonNumericArg = errors.New("expected numeric argument")
 )
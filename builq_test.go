@@ -1,7 +1,9 @@
 package builq
 
 import (
+	"database/sql"
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -54,7 +56,190 @@ func TestBuilder(t *testing.T) {
 	test("mixed placeholders", errMixedPlaceholders, "WHERE foo = %$ AND bar = %?", 1, 2)
 	test("non-slice argument", errNonSliceArgument, "WHERE foo = %+$", 1)
 	test("non-slice argument (batch)", errNonSliceArgument, "WHERE foo = %#$", 1)
+	test("empty slice", errEmptySlice, "WHERE foo IN (%+$)", []int{})
 	test("non-numeric argument", errNonNumericArg, "WHERE foo = %d", "a")
+	test("incorrect verb (expr)", errIncorrectVerb, "WHERE %e", 1)
+	test("non-expr argument", errNonExprArgument, "WHERE %e$", 1)
+}
+
+func TestVerbs(t *testing.T) {
+	verbs, err := Verbs("SELECT %s FROM t WHERE a = %$ AND b IN (%+$) AND c %% 2 = %d VALUES %#? %e@", LiteralVerbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"%s", "%$", "%+$", "%d", "%#?", "%e@"}
+	if strings.Join(verbs, " ") != strings.Join(want, " ") {
+		t.Fatalf("\nhave: %v\nwant: %v", verbs, want)
+	}
+
+	if _, err := Verbs("SELECT %x", LiteralVerbs); !errors.Is(err, errUnsupportedVerb) {
+		t.Fatalf("have %v, want %v", err, errUnsupportedVerb)
+	}
+
+	const like = "SELECT * FROM t WHERE a LIKE 'a%' AND b = %$ -- 100%"
+	if _, err := Verbs(like, LiteralVerbs); !errors.Is(err, errUnsupportedVerb) {
+		t.Fatalf("have %v, want %v", err, errUnsupportedVerb)
+	}
+	verbs, err = Verbs(like, LiteralText)
+	if err != nil || strings.Join(verbs, " ") != "%$" {
+		t.Fatalf("have %v, %v", verbs, err)
+	}
+	if _, err := Verbs(like, LiteralError); !errors.Is(err, errVerbInLiteral) {
+		t.Fatalf("have %v, want %v", err, errVerbInLiteral)
+	}
+}
+
+func TestBuilderLiterals(t *testing.T) {
+	test := func(mode LiteralMode, format string, args []any, want string, wantErr error) {
+		t.Helper()
+		var b Builder
+		b.Literals(mode)
+		b.Addf(constString(format), args...)
+		query, _, err := b.Build()
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("\nhave: %v\nwant: %v", err, wantErr)
+		}
+		if query != want {
+			t.Errorf("\nhave: %s\nwant: %s", query, want)
+		}
+	}
+
+	test(LiteralVerbs, "WHERE name LIKE '%s'", []any{"foo%"}, "WHERE name LIKE 'foo%'", nil)
+	test(LiteralText, "WHERE name LIKE '%$' AND id = %$", []any{1}, "WHERE name LIKE '%$' AND id = $1", nil)
+	test(LiteralText, "WHERE id = %$ -- 100%sure", []any{1}, "WHERE id = $1 -- 100%sure", nil)
+	test(LiteralText, "WHERE id = %$ /* 50%% */", []any{1}, "WHERE id = $1 /* 50%% */", nil)
+	test(LiteralText, `WHERE "100%" = %$ AND f = $$%s$$`, []any{1}, `WHERE "100%" = $1 AND f = $$%s$$`, nil)
+	test(LiteralText, "WHERE a = 'it''s %s' AND b = %d %% 2", []any{1}, "WHERE a = 'it''s %s' AND b = 1 % 2", nil)
+	test(LiteralText, "WHERE name = 'foo", nil, "", errUnterminatedLiteral)
+	test(LiteralError, "WHERE name LIKE '%$'", []any{1}, "", errVerbInLiteral)
+	test(LiteralError, "WHERE id = %$ -- 100%sure", []any{1}, "", errVerbInLiteral)
+	test(LiteralError, "WHERE name = 'foo' AND id = %$", []any{1}, "WHERE name = 'foo' AND id = $1", nil)
+}
+
+func TestBuilderNamedArgs(t *testing.T) {
+	var b Builder
+	b.NamedArgs(true)
+	b.Addf("SELECT * FROM users WHERE name = %@ AND age > %@", "john", 18)
+	b.Addf("AND id IN (%+@) AND org = %@", []int{1, 2}, sql.Named("org", 42))
+
+	query, args, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT * FROM users WHERE name = @p1 AND age > @p2\nAND id IN (@p3, @p4) AND org = @org"
+	if query != wantQuery {
+		t.Fatalf("\nhave: %s\nwant: %s", query, wantQuery)
+	}
+	wantArgs := []any{
+		sql.Named("p1", "john"), sql.Named("p2", 18),
+		sql.Named("p3", 1), sql.Named("p4", 2), sql.Named("org", 42),
+	}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("\nhave: %v\nwant: %v", args, wantArgs)
+	}
+
+	var plain Builder
+	plain.Addf("WHERE a = %@ AND b = %@ AND c = %@", "x", sql.Named("b", 2), 3)
+	query, args, err = plain.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "WHERE a = @p1 AND b = @b AND c = @p3" {
+		t.Fatalf("have %s", query)
+	}
+	if !reflect.DeepEqual(args, []any{"x", sql.Named("b", 2), 3}) {
+		t.Fatalf("have %v", args)
+	}
+	if debug := plain.DebugBuild(); debug != "WHERE a = 'x' AND b = 2 AND c = 3" {
+		t.Fatalf("have %s", debug)
+	}
+
+	var bad Builder
+	bad.Addf("WHERE a = %@", sql.Named("a; DROP TABLE users", 1))
+	if _, _, err := bad.Build(); !errors.Is(err, errInvalidArgName) {
+		t.Fatalf("have %v, want %v", err, errInvalidArgName)
+	}
+}
+
+func TestBuilderColon(t *testing.T) {
+	var b Builder
+	b.Addf("SELECT * FROM users WHERE name = %: AND id IN (%+:)", "john", []int{1, 2})
+	b.Addf("AND org = %:", sql.Named("org", 42))
+	b.Addf("INSERT INTO t VALUES %#:", [][]any{{1, "a"}, {2, "b"}})
+
+	query, args, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT * FROM users WHERE name = :1 AND id IN (:2, :3)\nAND org = :org\nINSERT INTO t VALUES (:5, :6), (:7, :8)"
+	if query != wantQuery {
+		t.Fatalf("\nhave: %s\nwant: %s", query, wantQuery)
+	}
+	wantArgs := []any{"john", 1, 2, sql.Named("org", 42), 1, "a", 2, "b"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("\nhave: %v\nwant: %v", args, wantArgs)
+	}
+
+	wantDebug := "SELECT * FROM users WHERE name = 'john' AND id IN (1, 2)\nAND org = 42\nINSERT INTO t VALUES (1, 'a'), (2, 'b')"
+	if debug := b.DebugBuild(); debug != wantDebug {
+		t.Fatalf("\nhave: %s\nwant: %s", debug, wantDebug)
+	}
+
+	var mixed Builder
+	mixed.Addf("WHERE a = %: AND b = %$", 1, 2)
+	if _, _, err := mixed.Build(); !errors.Is(err, errMixedPlaceholders) {
+		t.Fatalf("have %v, want %v", err, errMixedPlaceholders)
+	}
+}
+
+func TestBuilderEmptySlices(t *testing.T) {
+	test := func(mode EmptySliceMode, format string, want string, wantErr error) {
+		t.Helper()
+		var b Builder
+		b.EmptySlices(mode)
+		b.Addf(constString(format), []int{}, 1)
+		query, args, err := b.Build()
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("\nhave: %v\nwant: %v", err, wantErr)
+		}
+		if query != want {
+			t.Errorf("\nhave: %s\nwant: %s", query, want)
+		}
+		if err == nil && !reflect.DeepEqual(args, []any{1}) {
+			t.Errorf("have %v", args)
+		}
+	}
+
+	test(EmptySliceError, "WHERE id IN (%+$) AND org = %$", "", errEmptySlice)
+	test(EmptySliceNull, "WHERE id IN (%+$) AND org = %$", "WHERE id IN (NULL) AND org = $1", nil)
+	test(EmptySliceFalse, "WHERE id NOT IN (%+$) AND org = %$", "WHERE id NOT IN (SELECT NULL WHERE FALSE) AND org = $1", nil)
+	test(EmptySliceFalse, "WHERE id IN (%+?) AND org = %?", "WHERE id IN (SELECT NULL WHERE 1=0) AND org = ?", nil)
+	test(EmptySliceFalse, "WHERE id IN (%+@) AND org = %@", "WHERE id IN (SELECT NULL WHERE 1=0) AND org = @p1", nil)
+	test(EmptySliceFalse, "WHERE id IN (%+:) AND org = %:", "WHERE id IN (SELECT NULL FROM DUAL WHERE 1=0) AND org = :1", nil)
+
+	var b Builder
+	b.Addf("WHERE id IN (%+$) AND org = %$", []int{}, 1)
+	if debug := b.DebugBuild(); debug != "WHERE id IN (/* empty slice */) AND org = 1" {
+		t.Errorf("have %s", debug)
+	}
+}
+
+func TestNormalizeFormat(t *testing.T) {
+	test := func(format, want string) {
+		t.Helper()
+		if have := normalizeFormat(format); have != want {
+			t.Errorf("\nhave: %s\nwant: %s", have, want)
+		}
+	}
+
+	test("SELECT a", "SELECT a")
+	test("  SELECT a,\n\t\tb\n  FROM t  ", "SELECT a, b FROM t")
+	test("SELECT a -- legacy\nFROM t", "SELECT a /* legacy */ FROM t")
+	test("SELECT a --\nFROM t", "SELECT a /* */ FROM t")
+	test("SELECT a -- a */ b /* c", "SELECT a /* a * / b / * c */")
+	test("SELECT 'a\n  b', \"c  d\" /* e\n f */", "SELECT 'a\n  b', \"c  d\" /* e\n f */")
+	test("SELECT $$ a\n -- b $$\nFROM t", "SELECT $$ a\n -- b $$ FROM t")
+	test("SELECT  'a\n  b", "SELECT 'a\n  b")
 }
 
 func FuzzBuilder(f *testing.F) {
@@ -86,7 +271,11 @@ func FuzzBuilder(f *testing.F) {
 				errors.Is(err, errIncorrectVerb) ||
 				errors.Is(err, errMixedPlaceholders) ||
 				errors.Is(err, errNonSliceArgument) ||
-				errors.Is(err, errNonNumericArg) {
+				errors.Is(err, errEmptySlice) ||
+				errors.Is(err, errNonNumericArg) ||
+				errors.Is(err, errNonExprArgument) ||
+				errors.Is(err, errVerbInLiteral) ||
+				errors.Is(err, errUnterminatedLiteral) {
 				return
 			}
 			t.Fatalf("unexpected error: %v", err)
@@ -103,3 +292,31 @@ func FuzzBuilder(f *testing.F) {
 		checkArgs(2, 0)
 	})
 }
+
+func TestBuildTwice(t *testing.T) {
+	var b Builder
+	b.Addf("SELECT * FROM t WHERE id = %$ AND org IN (%+$)", 1, []int{2, 3})
+
+	query1, args1, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	query2, args2, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT * FROM t WHERE id = $1 AND org IN ($2, $3)"; query1 != want || query2 != want {
+		t.Fatalf("\nhave: %s\nhave: %s\nwant: %s", query1, query2, want)
+	}
+	if !reflect.DeepEqual(args1, args2) {
+		t.Fatalf("have %v and %v", args1, args2)
+	}
+
+	var mixed Builder
+	mixed.Addf("SELECT %$", 1)
+	mixed.Build()
+	mixed.Addf("AND %?", 2)
+	if _, _, err := mixed.Build(); !errors.Is(err, errMixedPlaceholders) {
+		t.Fatalf("have %v, want %v", err, errMixedPlaceholders)
+	}
+}
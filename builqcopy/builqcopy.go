@@ -0,0 +1,203 @@
+// Package builqcopy streams rows as PostgreSQL COPY FROM STDIN payload.
+//
+// Rows are the same slices of slices or structs as for builq `%#$` verb,
+// or a [builq.RowSource] to stream rows without loading them in memory.
+package builqcopy
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/cristalhq/builq"
+	"github.com/cristalhq/builq/internal/structs"
+	"github.com/cristalhq/builq/internal/values"
+)
+
+// Format of the payload.
+type Format byte
+
+const (
+	// Text is the default COPY format: tab separated columns, `\N` is NULL.
+	Text Format = iota
+
+	// CSV is the COPY CSV format: comma separated columns, unquoted empty field is NULL.
+	CSV
+)
+
+var (
+	// errColumnsMismatch when a row doesn't match columns.
+	errColumnsMismatch = errors.New("builqcopy: row doesn't match columns")
+
+	// errUnsupportedValue when a value cannot be encoded.
+	errUnsupportedValue = errors.New("builqcopy: unsupported value")
+
+	// errNonSliceRows when rows aren't a slice of slices or structs.
+	errNonSliceRows = errors.New("builqcopy: rows must be a slice of slices or structs")
+)
+
+// Statement returns `COPY table (cols) FROM STDIN` for the format.
+// Table and columns are written as is, they must be constants like formats in [builq.Builder.Addf].
+func Statement(table string, cols builq.Columns, format Format) string {
+	stmt := "COPY " + table + " (" + cols.String() + ") FROM STDIN"
+	if format == CSV {
+		stmt += " WITH (FORMAT csv)"
+	}
+	return stmt
+}
+
+// Writer writes rows in COPY format.
+type Writer struct {
+	w      *bufio.Writer
+	cols   int
+	format Format
+	buf    []byte
+}
+
+// NewWriter returns a writer of rows with cols columns, don't forget to [Writer.Flush] it.
+func NewWriter(w io.Writer, cols builq.Columns, format Format) *Writer {
+	return &Writer{
+		w:      bufio.NewWriter(w),
+		cols:   len(cols),
+		format: format,
+	}
+}
+
+// Write writes all rows and flushes the writer.
+// Rows must be a slice of slices or structs, struct fields are mapped by `db` tags as in builqsql.ColumnsOf.
+// Rows can be a [builq.RowSource] (like [builq.RowsSeq] for an iterator), it's read row by row.
+func Write(w io.Writer, cols builq.Columns, format Format, rows any) error {
+	cw := NewWriter(w, cols, format)
+
+	if source, ok := rows.(builq.RowSource); ok {
+		if s, ok := source.(interface{ Stop() }); ok {
+			defer s.Stop()
+		}
+		for i := 0; ; i++ {
+			row, ok := source.Next()
+			if !ok {
+				return cw.Flush()
+			}
+			if err := cw.WriteRow(row...); err != nil {
+				return fmt.Errorf("row %d: %w", i, err)
+			}
+		}
+	}
+
+	value := reflect.ValueOf(rows)
+	if value.Kind() != reflect.Slice {
+		return errNonSliceRows
+	}
+
+	for i := 0; i < value.Len(); i++ {
+		row, err := structs.Row(value.Index(i).Interface())
+		if err != nil {
+			if errors.Is(err, structs.ErrNotRow) {
+				return errNonSliceRows
+			}
+			return fmt.Errorf("builqcopy: %w", err)
+		}
+		if err := cw.WriteRow(row...); err != nil {
+			return fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+	return cw.Flush()
+}
+
+// WriteRow writes a single row.
+func (w *Writer) WriteRow(values ...any) error {
+	if len(values) != w.cols {
+		return fmt.Errorf("%w: have %d values, want %d", errColumnsMismatch, len(values), w.cols)
+	}
+
+	sep := byte('\t')
+	if w.format == CSV {
+		sep = ','
+	}
+
+	w.buf = w.buf[:0]
+	for i, v := range values {
+		if i > 0 {
+			w.buf = append(w.buf, sep)
+		}
+		var err error
+		if w.buf, err = w.appendValue(w.buf, v); err != nil {
+			return fmt.Errorf("column %d: %w", i+1, err)
+		}
+	}
+	w.buf = append(w.buf, '\n')
+
+	_, err := w.w.Write(w.buf)
+	return err
+}
+
+// Flush writes buffered rows to the underlying writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+func (w *Writer) appendValue(buf []byte, v any) ([]byte, error) {
+	s, isNull, err := encode(reflect.ValueOf(v))
+	switch {
+	case err != nil:
+		return nil, err
+	case isNull && w.format == CSV:
+		return buf, nil
+	case isNull:
+		return append(buf, `\N`...), nil
+	case w.format == CSV:
+		return appendCSV(buf, s), nil
+	default:
+		return appendText(buf, s), nil
+	}
+}
+
+// encode returns a value as a string before escaping.
+func encode(v reflect.Value) (s string, isNull bool, err error) {
+	v, isNull, err = values.Resolve(v)
+	if err != nil || isNull {
+		return "", isNull, err
+	}
+	s, _, err = values.Text(v)
+	if errors.Is(err, values.ErrUnsupported) {
+		return "", false, fmt.Errorf("%w: %s", errUnsupportedValue, v.Type())
+	}
+	return s, false, err
+}
+
+// appendText escapes backslashes, tabs and newlines.
+func appendText(buf []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			buf = append(buf, `\\`...)
+		case '\t':
+			buf = append(buf, `\t`...)
+		case '\n':
+			buf = append(buf, `\n`...)
+		case '\r':
+			buf = append(buf, `\r`...)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return buf
+}
+
+// appendCSV quotes a value when needed, an empty string is quoted to differ from NULL.
+func appendCSV(buf []byte, s string) []byte {
+	if s != "" && s != `\.` && !strings.ContainsAny(s, ",\"\n\r") {
+		return append(buf, s...)
+	}
+	buf = append(buf, '"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' {
+			buf = append(buf, '"')
+		}
+		buf = append(buf, s[i])
+	}
+	return append(buf, '"')
+}
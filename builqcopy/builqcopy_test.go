@@ -0,0 +1,126 @@
+package builqcopy
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cristalhq/builq"
+)
+
+var cols = builq.Columns{"id", "name", "data", "created_at"}
+
+func TestStatement(t *testing.T) {
+	mustEqual(t, Statement("users", cols, Text), "COPY users (id, name, data, created_at) FROM STDIN")
+	mustEqual(t, Statement("users", cols, CSV), "COPY users (id, name, data, created_at) FROM STDIN WITH (FORMAT csv)")
+}
+
+func TestWriteText(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	rows := [][]any{
+		{1, "plain", []byte{0xde, 0xad}, ts},
+		{2, "tab\there\nnew line\r\\slash", nil, (*time.Time)(nil)},
+		{int64(3), "", []byte{}, ts.In(time.FixedZone("", 3*3600))},
+	}
+
+	var sb strings.Builder
+	if err := Write(&sb, cols, Text, rows); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "1\tplain\t\\\\xdead\t2024-01-02 03:04:05.123456+00:00\n" +
+		"2\ttab\\there\\nnew line\\r\\\\slash\t\\N\t\\N\n" +
+		"3\t\t\\\\x\t2024-01-02 06:04:05.123456+03:00\n"
+	mustEqual(t, sb.String(), want)
+}
+
+func TestWriteCSV(t *testing.T) {
+	type row struct {
+		ID      int          `db:"id"`
+		Name    string       `db:"name"`
+		Data    []byte       `db:"data"`
+		Created sql.NullTime `db:"created_at"`
+		Hidden  string
+	}
+	rows := []row{
+		{ID: 1, Name: "plain", Data: []byte{1}},
+		{ID: 2, Name: `a,"b"` + "\nc"},
+		{ID: 3, Name: "", Created: sql.NullTime{Time: time.Unix(0, 0).UTC(), Valid: true}},
+		{ID: 4, Name: `\.`},
+	}
+
+	var sb strings.Builder
+	if err := Write(&sb, cols, CSV, rows); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "1,plain,\\x01,\n" +
+		"2,\"a,\"\"b\"\"\nc\",,\n" +
+		"3,\"\",,1970-01-01 00:00:00+00:00\n" +
+		"4,\"\\.\",,\n"
+	mustEqual(t, sb.String(), want)
+}
+
+func TestWriteValues(t *testing.T) {
+	name := "john"
+	var sb strings.Builder
+	w := NewWriter(&sb, builq.Columns{"a", "b", "c", "d", "e", "f", "g"}, Text)
+	if err := w.WriteRow(true, false, 1.5, math.Inf(-1), math.NaN(), &name, uint8(7)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	mustEqual(t, sb.String(), "t\tf\t1.5\t-Infinity\tNaN\tjohn\t7\n")
+}
+
+func TestWriteErrors(t *testing.T) {
+	var sb strings.Builder
+
+	err := Write(&sb, cols, Text, 1)
+	if !errors.Is(err, errNonSliceRows) {
+		t.Fatalf("have %v, want %v", err, errNonSliceRows)
+	}
+
+	err = Write(&sb, cols, Text, [][]any{{1, "a"}})
+	if !errors.Is(err, errColumnsMismatch) {
+		t.Fatalf("have %v, want %v", err, errColumnsMismatch)
+	}
+
+	err = Write(&sb, builq.Columns{"a"}, Text, [][]any{{map[string]int{}}})
+	if !errors.Is(err, errUnsupportedValue) {
+		t.Fatalf("have %v, want %v", err, errUnsupportedValue)
+	}
+}
+
+func mustEqual(t testing.TB, have, want any) {
+	t.Helper()
+	if have != want {
+		t.Fatalf("\nhave: %v\nwant: %v", have, want)
+	}
+}
+
+func TestWriteRowSource(t *testing.T) {
+	n := 0
+	source := builq.RowsFunc(func() ([]any, bool) {
+		if n == 3 {
+			return nil, false
+		}
+		n++
+		return []any{n, "row", nil, nil}, true
+	})
+
+	var sb strings.Builder
+	if err := Write(&sb, cols, CSV, source); err != nil {
+		t.Fatal(err)
+	}
+	mustEqual(t, sb.String(), "1,row,,\n2,row,,\n3,row,,\n")
+
+	bad := builq.RowsFunc(func() ([]any, bool) { return []any{1}, true })
+	if err := Write(&sb, cols, CSV, bad); !errors.Is(err, errColumnsMismatch) {
+		t.Fatalf("have %v, want %v", err, errColumnsMismatch)
+	}
+}
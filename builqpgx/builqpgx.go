@@ -0,0 +1,118 @@
+// Package builqpgx integrates builq with pgx.
+//
+// It's a separate module to keep builq free of dependencies.
+package builqpgx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/cristalhq/builq"
+	"github.com/jackc/pgx/v5"
+)
+
+// Builder is implemented by [*builq.Builder], [*builq.OnelineBuilder] and [builq.BuildFn].
+type Builder interface {
+	Build() (query string, args []any, err error)
+}
+
+var (
+	// errNonEmptySQL when SQL or arguments are passed together with [Rewrite].
+	errNonEmptySQL = errors.New("builqpgx: sql and arguments must be empty, the query is built by builq")
+
+	// errNotDollar when [NamedArgs] query isn't built with `%$` or `%@` placeholders.
+	errNotDollar = errors.New("builqpgx: named args require `%$` or `%@` placeholders")
+
+	// errNamedDollar when [sql.NamedArg] is passed to a `%$` placeholder in [NamedArgs].
+	errNamedDollar = errors.New("builqpgx: sql.NamedArg requires `%@` placeholder")
+)
+
+// Rewrite returns [pgx.QueryRewriter] that builds b, so the builder can be passed
+// straight to pgx query methods with an empty SQL:
+//
+//	rows, err := conn.Query(ctx, "", builqpgx.Rewrite(&b))
+func Rewrite(b Builder) pgx.QueryRewriter {
+	return rewriter{b: b}
+}
+
+type rewriter struct {
+	b Builder
+}
+
+func (r rewriter) RewriteQuery(ctx context.Context, conn *pgx.Conn, sql string, args []any) (string, []any, error) {
+	if sql != "" || len(args) > 0 {
+		return "", nil, errNonEmptySQL
+	}
+	return r.b.Build()
+}
+
+// Queue builds b and queues the query into batch.
+// Every builder is built separately, so each query has its own `$1, $2, ...` numbering.
+func Queue(batch *pgx.Batch, b Builder) (*pgx.QueuedQuery, error) {
+	query, args, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return batch.Queue(query, args...), nil
+}
+
+// NewBatch returns [pgx.Batch] with queries of the builders queued in order.
+func NewBatch(bs ...Builder) (*pgx.Batch, error) {
+	batch := &pgx.Batch{}
+	for i, b := range bs {
+		if _, err := Queue(batch, b); err != nil {
+			return nil, fmt.Errorf("builqpgx: query %d: %w", i, err)
+		}
+	}
+	return batch, nil
+}
+
+// NamedArgs builds b and returns the query with `@name` placeholders and [pgx.NamedArgs].
+//
+// With `%$` placeholders the query is rewritten to `@p1, @p2, ...` and arguments are named p1, p2, ...
+// With `%@` placeholders the query is kept as is: [sql.NamedArg] is keyed by its name
+// and other arguments by their `@pN` placeholder.
+// [sql.NamedArg] cannot be used with `%$` placeholders.
+func NamedArgs(b Builder) (string, pgx.NamedArgs, error) {
+	query, args, err := b.Build()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(args) == 0 {
+		return query, pgx.NamedArgs{}, nil
+	}
+
+	named, rebindErr := builq.Rebind(query, builq.StyleDollar, builq.StyleAt)
+	isDollar := rebindErr == nil && named != query
+	if !isDollar {
+		named = query
+	}
+
+	namedArgs := make(pgx.NamedArgs, len(args))
+	var positional bool
+	for i, arg := range args {
+		if arg, ok := arg.(sql.NamedArg); ok {
+			if isDollar {
+				return "", nil, errNamedDollar
+			}
+			namedArgs[arg.Name] = arg.Value
+			continue
+		}
+		positional = true
+		namedArgs["p"+strconv.Itoa(i+1)] = arg
+	}
+
+	if positional && !isDollar {
+		// `%@` placeholders are `@pN`, same as `%$` rebound to StyleAt.
+		if dollar, err := builq.Rebind(query, builq.StyleAt, builq.StyleDollar); err != nil || dollar == query {
+			if rebindErr != nil {
+				return "", nil, fmt.Errorf("builqpgx: %w", rebindErr)
+			}
+			return "", nil, errNotDollar
+		}
+	}
+	return named, namedArgs, nil
+}
@@ -0,0 +1,156 @@
+package builqpgx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cristalhq/builq"
+	"github.com/jackc/pgx/v5"
+)
+
+// fakeConn handles [pgx.QueryRewriter] like [pgx.Conn] does and records the query.
+type fakeConn struct {
+	sql  string
+	args []any
+}
+
+func (c *fakeConn) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if len(args) > 0 {
+		if r, ok := args[0].(pgx.QueryRewriter); ok {
+			var err error
+			sql, args, err = r.RewriteQuery(ctx, nil, sql, args[1:])
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	c.sql, c.args = sql, args
+	return nil, nil
+}
+
+func TestRewrite(t *testing.T) {
+	var b builq.Builder
+	b.Addf("SELECT * FROM users WHERE id IN (%+$) AND active = %$", []int{1, 2}, true)
+
+	conn := &fakeConn{}
+	if _, err := conn.Query(context.Background(), "", Rewrite(&b)); err != nil {
+		t.Fatal(err)
+	}
+	mustEqual(t, conn.sql, "SELECT * FROM users WHERE id IN ($1, $2) AND active = $3")
+	mustEqual(t, conn.args, []any{1, 2, true})
+
+	_, err := conn.Query(context.Background(), "SELECT 1", Rewrite(&b))
+	mustEqual(t, err, errNonEmptySQL)
+
+	var bad builq.Builder
+	bad.Addf("SELECT %$")
+	if _, err := conn.Query(context.Background(), "", Rewrite(&bad)); err == nil {
+		t.Fatal("must fail")
+	}
+}
+
+func TestNewBatch(t *testing.T) {
+	q1 := builq.New()
+	q1("INSERT INTO users (name) VALUES (%$)", "john")
+
+	q2 := builq.New()
+	q2("UPDATE users SET name = %$ WHERE id = %$", "jane", 42)
+
+	batch, err := NewBatch(q1, q2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b builq.Builder
+	b.Addf("DELETE FROM users WHERE id = %$", 1)
+	if _, err := Queue(batch, &b); err != nil {
+		t.Fatal(err)
+	}
+
+	var queries []string
+	var args [][]any
+	for _, q := range batch.QueuedQueries {
+		queries = append(queries, q.SQL)
+		args = append(args, q.Arguments)
+	}
+	mustEqual(t, queries, []string{
+		"INSERT INTO users (name) VALUES ($1)",
+		"UPDATE users SET name = $1 WHERE id = $2",
+		"DELETE FROM users WHERE id = $1",
+	})
+	mustEqual(t, args, [][]any{{"john"}, {"jane", 42}, {1}})
+
+	var bad builq.Builder
+	bad.Addf("SELECT %X", 1)
+	if _, err := NewBatch(q1, &bad); err == nil {
+		t.Fatal("must fail")
+	}
+}
+
+func TestNamedArgs(t *testing.T) {
+	var b builq.Builder
+	b.Addf("SELECT * FROM users WHERE name = %$ AND tags @> %$", "john", []string{"admin"})
+	b.Addf("AND note = 'costs $1'")
+
+	query, args, err := NamedArgs(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustEqual(t, query, "SELECT * FROM users WHERE name = @p1 AND tags @> @p2\nAND note = 'costs $1'")
+	mustEqual(t, args, pgx.NamedArgs{"p1": "john", "p2": []string{"admin"}})
+
+	conn := &fakeConn{}
+	if _, err := conn.Query(context.Background(), query, args); err != nil {
+		t.Fatal(err)
+	}
+	mustEqual(t, conn.sql, "SELECT * FROM users WHERE name = $1 AND tags @> $2\nAND note = 'costs $1'")
+	mustEqual(t, conn.args, []any{"john", []string{"admin"}})
+
+	var q builq.Builder
+	q.Addf("SELECT * FROM users WHERE name = %?", "john")
+	if _, _, err := NamedArgs(&q); !errors.Is(err, errNotDollar) {
+		t.Fatalf("have %v, want %v", err, errNotDollar)
+	}
+
+	var at builq.Builder
+	at.Addf("SELECT * FROM users WHERE id = %@ AND org = %@", sql.Named("id", 42), "acme")
+
+	query, args, err = NamedArgs(&at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustEqual(t, query, "SELECT * FROM users WHERE id = @id AND org = @p2")
+	mustEqual(t, args, pgx.NamedArgs{"id": 42, "p2": "acme"})
+
+	conn = &fakeConn{}
+	if _, err := conn.Query(context.Background(), query, args); err != nil {
+		t.Fatal(err)
+	}
+	mustEqual(t, conn.sql, "SELECT * FROM users WHERE id = $1 AND org = $2")
+	mustEqual(t, conn.args, []any{42, "acme"})
+
+	var mixed builq.Builder
+	mixed.Addf("SELECT * FROM users WHERE id = %$", sql.Named("id", 42))
+	if _, _, err := NamedArgs(&mixed); !errors.Is(err, errNamedDollar) {
+		t.Fatalf("have %v, want %v", err, errNamedDollar)
+	}
+
+	var empty builq.Builder
+	empty.Addf("SELECT 1")
+	query, args, err = NamedArgs(&empty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustEqual(t, query, "SELECT 1")
+	mustEqual(t, args, pgx.NamedArgs{})
+}
+
+func mustEqual(t testing.TB, have, want any) {
+	t.Helper()
+	if !reflect.DeepEqual(have, want) {
+		t.Fatalf("\nhave: %+v\nwant: %+v", have, want)
+	}
+}
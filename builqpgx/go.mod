@@ -0,0 +1,15 @@
+module github.com/cristalhq/builq/builqpgx
+
+go 1.19
+
+require (
+	github.com/cristalhq/builq v0.0.0-20261019063417-9a2ad3502674
+	github.com/jackc/pgx/v5 v5.5.5
+)
+
+require (
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20221227161230-091c0ba34f0a // indirect
+	golang.org/x/crypto v0.17.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
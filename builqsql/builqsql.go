@@ -0,0 +1,94 @@
+// Package builqsql provides database/sql helpers for builq builders.
+package builqsql
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Querier is implemented by [*sql.DB], [*sql.Tx] and [*sql.Conn].
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Builder is implemented by [*builq.Builder], [*builq.OnelineBuilder] and [builq.BuildFn].
+type Builder interface {
+	Build() (query string, args []any, err error)
+}
+
+// Error is returned when a query cannot be built or executed.
+type Error struct {
+	// Query with placeholders, arguments are never included so it's safe to log.
+	// When the query cannot be built, this is the query shape (see [builq.Builder.Fingerprint]) if available.
+	Query string
+	Err   error
+}
+
+func (e *Error) Error() string {
+	if e.Query == "" {
+		return "builqsql: " + e.Err.Error()
+	}
+	return "builqsql: " + e.Err.Error() + ": " + e.Query
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Exec builds and executes a query without returning any rows.
+func Exec(ctx context.Context, db Querier, b Builder) (sql.Result, error) {
+	query, args, err := build(b)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, &Error{Query: query, Err: err}
+	}
+	return res, nil
+}
+
+// Query builds and executes a query that returns rows.
+func Query(ctx context.Context, db Querier, b Builder) (*sql.Rows, error) {
+	query, args, err := build(b)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, &Error{Query: query, Err: err}
+	}
+	return rows, nil
+}
+
+// QueryRow builds and executes a query that returns at most one row and scans it into dest.
+// [sql.ErrNoRows] is returned (wrapped) when there are no rows.
+func QueryRow(ctx context.Context, db Querier, b Builder, dest ...any) error {
+	query, args, err := build(b)
+	if err != nil {
+		return err
+	}
+
+	if err := db.QueryRowContext(ctx, query, args...).Scan(dest...); err != nil {
+		return &Error{Query: query, Err: err}
+	}
+	return nil
+}
+
+func build(b Builder) (string, []any, error) {
+	query, args, err := b.Build()
+	if err == nil {
+		return query, args, nil
+	}
+
+	if f, ok := b.(interface {
+		Fingerprint() (string, uint64, error)
+	}); ok {
+		query, _, _ = f.Fingerprint()
+	}
+	return "", nil, &Error{Query: query, Err: err}
+}
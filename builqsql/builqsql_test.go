@@ -0,0 +1,149 @@
+package builqsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cristalhq/builq"
+)
+
+func TestExec(t *testing.T) {
+	fake := &fakeDB{}
+	db := openFake(t, fake)
+
+	var b builq.Builder
+	b.Addf("UPDATE users SET name = %$ WHERE id = %$", "john", 42)
+
+	res, err := Exec(context.Background(), db, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := res.RowsAffected(); n != 1 {
+		t.Fatalf("have %d rows affected", n)
+	}
+
+	mustEqual(t, fake.queries, []string{"UPDATE users SET name = $1 WHERE id = $2"})
+	mustEqual(t, fake.args, [][]any{{"john", 42}})
+}
+
+func TestQuery(t *testing.T) {
+	fake := &fakeDB{
+		columns: []string{"id", "name"},
+		rows:    [][]driver.Value{{int64(1), "john"}, {int64(2), "jane"}},
+	}
+	db := openFake(t, fake)
+
+	q := builq.New()
+	q("SELECT id, name FROM users WHERE id IN (%+$)", []int{1, 2})
+
+	rows, err := Query(context.Background(), db, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	mustEqual(t, names, []string{"john", "jane"})
+	mustEqual(t, fake.queries, []string{"SELECT id, name FROM users WHERE id IN ($1, $2)"})
+}
+
+func TestQueryRow(t *testing.T) {
+	fake := &fakeDB{
+		columns: []string{"count"},
+		rows:    [][]driver.Value{{int64(42)}},
+	}
+	db := openFake(t, fake)
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	var b builq.Builder
+	b.Addf("SELECT count(*) FROM users WHERE age > %$", 18)
+
+	var count int
+	if err := QueryRow(context.Background(), tx, &b, &count); err != nil {
+		t.Fatal(err)
+	}
+	mustEqual(t, count, 42)
+
+	fake.rows = nil
+	err = QueryRow(context.Background(), tx, &b, &count)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("have %v, want %v", err, sql.ErrNoRows)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	errDriver := errors.New("driver error")
+	fake := &fakeDB{err: errDriver}
+	db := openFake(t, fake)
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var b builq.Builder
+	b.Addf("DELETE FROM users WHERE name = %$", "secret")
+
+	_, err = Exec(context.Background(), conn, &b)
+	var qerr *Error
+	if !errors.As(err, &qerr) || !errors.Is(err, errDriver) {
+		t.Fatalf("have %v", err)
+	}
+	mustEqual(t, qerr.Query, "DELETE FROM users WHERE name = $1")
+	if strings.Contains(err.Error(), "secret") {
+		t.Fatalf("arguments must not leak: %v", err)
+	}
+
+	var bad builq.Builder
+	bad.Addf("SELECT * FROM users WHERE id IN (%+$)", "secret")
+
+	_, err = Query(context.Background(), db, &bad)
+	if !errors.As(err, &qerr) {
+		t.Fatalf("have %v", err)
+	}
+	mustEqual(t, qerr.Query, "SELECT * FROM users WHERE id IN (?...)")
+	if len(fake.queries) != 1 {
+		t.Fatal("query must not be executed")
+	}
+
+	// BuildFn has no Fingerprint, so there is no query to report.
+	fn := builq.New()
+	fn("SELECT * FROM users WHERE id IN (%+$)", "secret")
+
+	err = QueryRow(context.Background(), db, fn)
+	if !errors.As(err, &qerr) {
+		t.Fatalf("have %v", err)
+	}
+	mustEqual(t, qerr.Query, "")
+	mustEqual(t, err.Error(), "builqsql: "+qerr.Err.Error())
+}
+
+func mustEqual(t testing.TB, have, want any) {
+	t.Helper()
+	if !reflect.DeepEqual(have, want) {
+		t.Fatalf("\nhave: %+v\nwant: %+v", have, want)
+	}
+}
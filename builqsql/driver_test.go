@@ -0,0 +1,140 @@
+package builqsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func init() {
+	sql.Register("builqsql-fake", fakeDriver{})
+}
+
+var (
+	fakeDBs   sync.Map
+	fakeDBSeq atomic.Int64
+)
+
+// fakeDB is an in-process database: it records queries and returns configured rows.
+type fakeDB struct {
+	mu      sync.Mutex
+	queries []string
+	args    [][]any
+	columns []string
+	rows    [][]driver.Value
+	err     error // returned on exec and query.
+	rowsErr error // returned after the last row.
+	open    int   // rows not closed yet.
+}
+
+// openFake returns *sql.DB backed by fakeDB.
+func openFake(t testing.TB, fake *fakeDB) *sql.DB {
+	t.Helper()
+	name := strconv.FormatInt(fakeDBSeq.Add(1), 10)
+	fakeDBs.Store(name, fake)
+
+	db, err := sql.Open("builqsql-fake", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func (f *fakeDB) record(query string, args []driver.NamedValue) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values := make([]any, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	f.queries = append(f.queries, query)
+	f.args = append(f.args, values)
+	return f.err
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	db, ok := fakeDBs.Load(name)
+	if !ok {
+		return nil, errors.New("unknown fake db " + name)
+	}
+	return &fakeConn{db: db.(*fakeDB)}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.db.record(query, args); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.db.record(query, args); err != nil {
+		return nil, err
+	}
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.open++
+	return &fakeRows{db: c.db, columns: c.db.columns, rows: c.db.rows, err: c.db.rowsErr}, nil
+}
+
+// CheckNamedValue accepts any argument as is.
+func (c *fakeConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct {
+	db      *fakeDB
+	columns []string
+	rows    [][]driver.Value
+	err     error
+	closed  bool
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error {
+	if !r.closed {
+		r.closed = true
+		r.db.mu.Lock()
+		r.db.open--
+		r.db.mu.Unlock()
+	}
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		if r.err != nil {
+			return r.err
+		}
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
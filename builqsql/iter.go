@@ -0,0 +1,57 @@
+//go:build go1.23
+
+package builqsql
+
+import (
+	"context"
+	"iter"
+)
+
+// All builds and executes the query and yields rows mapped by scan one by one.
+// When scan is nil, rows are scanned into T by `db` tags, see [ScanAll].
+//
+// The query is built on every range, so the Seq can be ranged again.
+// Rows are closed when the loop ends or breaks. Errors, including [sql.Rows.Err],
+// are yielded with a zero T as the last element:
+//
+//	for user, err := range builqsql.All[User](ctx, db, &b, nil) {
+//		if err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+func All[T any](ctx context.Context, db Querier, b Builder, scan func(Rows) (T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		rows, err := Query(ctx, db, b)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+
+		// scan is captured by the Seq, so it must not be reassigned between ranges.
+		scanRow := scan
+		if scanRow == nil {
+			if scanRow, err = Scanner[T](rows); err != nil {
+				yield(zero, err)
+				return
+			}
+		}
+
+		for rows.Next() {
+			v, err := scanRow(rows)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+//go:build go1.23
+
+package builqsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/cristalhq/builq"
+)
+
+func TestAll(t *testing.T) {
+	type row struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	fake := &fakeDB{
+		columns: []string{"id", "name"},
+		rows:    [][]driver.Value{{int64(1), "john"}, {int64(2), "jane"}, {int64(3), "bob"}},
+	}
+	db := openFake(t, fake)
+
+	var b builq.Builder
+	b.Addf("SELECT %s FROM users WHERE id > %$", ColumnsOf[row](), 0)
+
+	var rows []row
+	for r, err := range All[row](context.Background(), db, &b, nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows = append(rows, r)
+	}
+	mustEqual(t, rows, []row{{1, "john"}, {2, "jane"}, {3, "bob"}})
+	mustEqual(t, fake.queries, []string{"SELECT id, name FROM users WHERE id > $1"})
+
+	seq := All[row](context.Background(), db, &b, nil)
+	for range 2 {
+		rows = rows[:0]
+		for r, err := range seq {
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows = append(rows, r)
+		}
+		mustEqual(t, len(rows), 3)
+	}
+	if _, err := Exec(context.Background(), db, &b); err != nil {
+		t.Fatal(err)
+	}
+	const query = "SELECT id, name FROM users WHERE id > $1"
+	mustEqual(t, fake.queries, []string{query, query, query, query})
+
+	var names []string
+	name := func(rows Rows) (string, error) {
+		var id int64
+		var name string
+		err := rows.Scan(&id, &name)
+		return name, err
+	}
+	for n, err := range All(context.Background(), db, &b, name) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, n)
+		if len(names) == 2 {
+			break
+		}
+	}
+	mustEqual(t, names, []string{"john", "jane"})
+	mustEqual(t, fake.open, 0)
+}
+
+func TestAllErrors(t *testing.T) {
+	errRows := errors.New("connection reset")
+	fake := &fakeDB{
+		columns: []string{"id"},
+		rows:    [][]driver.Value{{int64(1)}},
+		rowsErr: errRows,
+	}
+	db := openFake(t, fake)
+
+	var b builq.Builder
+	b.Addf("SELECT id FROM users")
+
+	id := func(rows Rows) (int64, error) {
+		var id int64
+		err := rows.Scan(&id)
+		return id, err
+	}
+
+	var ids []int64
+	var errs []error
+	for v, err := range All(context.Background(), db, &b, id) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, v)
+	}
+	mustEqual(t, ids, []int64{1})
+	if len(errs) != 1 || !errors.Is(errs[0], errRows) {
+		t.Fatalf("have %v, want %v", errs, errRows)
+	}
+	mustEqual(t, fake.open, 0)
+
+	var bad builq.Builder
+	bad.Addf("SELECT id FROM users WHERE id = %X", 1)
+
+	for _, err := range All(context.Background(), db, &bad, id) {
+		var qerr *Error
+		if !errors.As(err, &qerr) {
+			t.Fatalf("have %v", err)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package builqsql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cristalhq/builq"
+	"github.com/cristalhq/builq/internal/structs"
+)
+
+// Rows is implemented by [*sql.Rows].
+type Rows interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+// ColumnsError is returned when result columns don't match struct fields.
+type ColumnsError struct {
+	Type    string
+	Missing []string // fields without a result column.
+	Extra   []string // result columns without a field.
+}
+
+func (e *ColumnsError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("builqsql: columns mismatch for ")
+	sb.WriteString(e.Type)
+	if len(e.Missing) > 0 {
+		sb.WriteString(", missing: ")
+		sb.WriteString(strings.Join(e.Missing, ", "))
+	}
+	if len(e.Extra) > 0 {
+		sb.WriteString(", extra: ")
+		sb.WriteString(strings.Join(e.Extra, ", "))
+	}
+	return sb.String()
+}
+
+// ColumnsOf returns columns of T from `db` tags in the field order.
+// Use it in `%s` to keep the query and the struct in sync:
+//
+//	var userColumns = builqsql.ColumnsOf[User]()
+//
+//	b.Addf("SELECT %s FROM users", userColumns)
+//
+// Fields without a tag or with `db:"-"` are skipped, embedded structs are flattened.
+// It panics if T isn't a struct.
+func ColumnsOf[T any]() builq.Columns {
+	fields, err := fieldsOf(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		panic(err)
+	}
+	cols := make(builq.Columns, len(fields))
+	for i, f := range fields {
+		cols[i] = f.Name
+	}
+	return cols
+}
+
+// ScanOne scans the first row into T and closes rows.
+// [sql.ErrNoRows] is returned when there are no rows.
+// Result columns must match `db` tags of T, see [ColumnsError].
+func ScanOne[T any](rows Rows) (T, error) {
+	defer rows.Close()
+
+	var v T
+	scan, err := Scanner[T](rows)
+	if err != nil {
+		return v, err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return v, err
+		}
+		return v, sql.ErrNoRows
+	}
+	if v, err = scan(rows); err != nil {
+		return v, err
+	}
+	return v, rows.Close()
+}
+
+// ScanAll scans all rows into a slice of T and closes rows.
+// Result columns must match `db` tags of T, see [ColumnsError].
+func ScanAll[T any](rows Rows) ([]T, error) {
+	defer rows.Close()
+
+	scan, err := Scanner[T](rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []T
+	for rows.Next() {
+		v, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return res, rows.Close()
+}
+
+// Scanner returns a function which scans the current row into T.
+// Columns of rows are matched with `db` tags of T once.
+func Scanner[T any](rows Rows) (func(Rows) (T, error), error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	fields, err := fieldsOf(typ)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string][]int, len(fields))
+	for _, f := range fields {
+		byName[f.Name] = f.Index
+	}
+
+	colErr := &ColumnsError{Type: typ.String()}
+	index := make([][]int, len(columns))
+	for i, col := range columns {
+		idx, ok := byName[col]
+		if !ok {
+			colErr.Extra = append(colErr.Extra, col)
+			continue
+		}
+		delete(byName, col)
+		index[i] = idx
+	}
+	for _, f := range fields {
+		if _, ok := byName[f.Name]; ok {
+			colErr.Missing = append(colErr.Missing, f.Name)
+		}
+	}
+	if len(colErr.Missing) > 0 || len(colErr.Extra) > 0 {
+		return nil, colErr
+	}
+
+	return func(rows Rows) (T, error) {
+		var v T
+		rv := reflect.ValueOf(&v).Elem()
+		dest := make([]any, len(index))
+		for i, idx := range index {
+			dest[i] = rv.FieldByIndex(idx).Addr().Interface()
+		}
+		err := rows.Scan(dest...)
+		return v, err
+	}, nil
+}
+
+func fieldsOf(typ reflect.Type) ([]structs.Field, error) {
+	fields, err := structs.Fields(typ)
+	if err != nil {
+		return nil, fmt.Errorf("builqsql: %w", err)
+	}
+	return fields, nil
+}
@@ -0,0 +1,128 @@
+package builqsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cristalhq/builq"
+)
+
+type testModel struct {
+	Created time.Time `db:"created_at"`
+}
+
+type testUser struct {
+	ID    int64          `db:"id"`
+	Name  string         `db:"name"`
+	Email sql.NullString `db:"email"`
+	Skip  string         `db:"-"`
+	Other string
+	testModel
+}
+
+func TestColumnsOf(t *testing.T) {
+	cols := ColumnsOf[testUser]()
+	mustEqual(t, cols, builq.Columns{"id", "name", "email", "created_at"})
+}
+
+func TestScanAll(t *testing.T) {
+	now := time.Now().UTC()
+	fake := &fakeDB{
+		columns: []string{"id", "name", "email", "created_at"},
+		rows: [][]driver.Value{
+			{int64(1), "john", nil, now},
+			{int64(2), "jane", "jane@example.com", now},
+		},
+	}
+	db := openFake(t, fake)
+
+	var b builq.Builder
+	b.Addf("SELECT %s FROM users", ColumnsOf[testUser]())
+
+	rows, err := Query(context.Background(), db, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := ScanAll[testUser](rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []testUser{
+		{ID: 1, Name: "john", testModel: testModel{Created: now}},
+		{ID: 2, Name: "jane", Email: sql.NullString{String: "jane@example.com", Valid: true}, testModel: testModel{Created: now}},
+	}
+	mustEqual(t, users, want)
+	mustEqual(t, fake.queries, []string{"SELECT id, name, email, created_at FROM users"})
+}
+
+func TestScanOne(t *testing.T) {
+	type row struct {
+		Name  string `db:"name"`
+		Count int    `db:"count"`
+	}
+
+	fake := &fakeDB{
+		columns: []string{"count", "name"},
+		rows:    [][]driver.Value{{int64(10), "john"}, {int64(20), "jane"}},
+	}
+	db := openFake(t, fake)
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ScanOne[row](rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustEqual(t, r, row{Name: "john", Count: 10})
+
+	fake.rows = nil
+	rows, err = db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ScanOne[row](rows)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("have %v, want %v", err, sql.ErrNoRows)
+	}
+}
+
+func TestScanColumnsError(t *testing.T) {
+	fake := &fakeDB{
+		columns: []string{"id", "login", "email", "created_at"},
+		rows:    [][]driver.Value{{int64(1), "john", nil, time.Now()}},
+	}
+	db := openFake(t, fake)
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ScanAll[testUser](rows)
+
+	var colErr *ColumnsError
+	if !errors.As(err, &colErr) {
+		t.Fatalf("have %v", err)
+	}
+	mustEqual(t, colErr.Missing, []string{"name"})
+	mustEqual(t, colErr.Extra, []string{"login"})
+	mustEqual(t, err.Error(), "builqsql: columns mismatch for builqsql.testUser, missing: name, extra: login")
+}
+
+func TestScanNotStruct(t *testing.T) {
+	db := openFake(t, &fakeDB{columns: []string{"id"}})
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ScanAll[int](rows); err == nil {
+		t.Fatal("must fail")
+	}
+}
@@ -0,0 +1,267 @@
+package builq
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cristalhq/builq/internal/structs"
+)
+
+// Parameter limits of popular databases, see [Builder.Chunks].
+const (
+	LimitPostgres     = 65535
+	LimitMSSQL        = 2100
+	LimitSQLite       = 32766 // since SQLite 3.32.0.
+	LimitSQLiteLegacy = 999
+)
+
+// Chunks splits a query with exactly one `%#` verb into queries with at most limit placeholders each.
+// Every chunk has a part of the batch rows and placeholders numbered from 1:
+//
+//	b.Addf("INSERT INTO users (name, age) VALUES %#$", rows)
+//
+//	chunks := b.Chunks(builq.LimitPostgres)
+//	defer chunks.Close()
+//	for chunks.Next() {
+//		query, args := chunks.Query()
+//		// ...
+//	}
+//	if err := chunks.Err(); err != nil {
+//		// ...
+//	}
+//
+// Rows are slices or structs, struct fields are mapped by `db` tags as in builqsql.ColumnsOf.
+// The batch argument can be a [RowSource] to stream rows, chunks are built lazily.
+// Close stops the source when the loop ends early, like on an Exec error.
+// An empty batch gives no chunks.
+func (b *Builder) Chunks(limit int) *Chunks {
+	c := &Chunks{b: b, limit: limit}
+	c.err = c.init()
+	return c
+}
+
+// Chunks of a batch query. See [Builder.Chunks].
+type Chunks struct {
+	b     *Builder
+	limit int
+	part  int // index of the part with the batch verb.
+	arg   int // index of the batch argument in the part.
+	fixed int // placeholders outside of the batch.
+
+	rows    []any     // rows of the batch left.
+	source  RowSource // or a source of rows.
+	pending any       // a row that didn't fit the previous chunk.
+	hasRow  bool      // is pending set.
+
+	query  string
+	args   []any
+	err    error
+	closed bool
+}
+
+// Next builds the next chunk, returns false when there are no more chunks or on error.
+// With [RowSource] only the rows of the current chunk are kept in memory.
+func (c *Chunks) Next() bool {
+	if c.err != nil || c.closed {
+		return false
+	}
+
+	var rows []any
+	size := c.fixed
+	for {
+		row, ok := c.pull()
+		if !ok {
+			break
+		}
+		width, err := c.b.rowWidth(row)
+		if err != nil {
+			c.fail(err)
+			return false
+		}
+		if size+width > c.limit {
+			if len(rows) == 0 {
+				c.fail(fmt.Errorf("%w: need %d placeholders, limit is %d", errChunkLimit, size+width, c.limit))
+				return false
+			}
+			c.push(row)
+			break
+		}
+		size += width
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		c.stop()
+		return false
+	}
+
+	c.query, c.args, c.err = c.build(rows)
+	if c.err != nil {
+		c.stop()
+	}
+	return c.err == nil
+}
+
+// Query returns the query and arguments of the current chunk.
+func (c *Chunks) Query() (query string, args []any) {
+	return c.query, c.args
+}
+
+// Close stops the [RowSource] of the batch, if any. It's safe to call it many times.
+// Further calls to Next return false.
+func (c *Chunks) Close() error {
+	c.closed = true
+	c.stop()
+	return nil
+}
+
+// Err returns the first error, if any.
+func (c *Chunks) Err() error {
+	return c.err
+}
+
+func (c *Chunks) init() error {
+	if err := c.b.findBatch(&c.part, &c.arg); err != nil {
+		return err
+	}
+
+	arg := c.b.args[c.part][c.arg]
+	if src, ok := arg.(RowSource); ok {
+		if c.b.sourceRead {
+			return errRowSourceRead
+		}
+		c.b.sourceRead = true
+		c.source = src
+	} else {
+		rows, err := c.b.asSlice(arg)
+		if err != nil {
+			return err
+		}
+		c.rows = rows
+	}
+
+	row, ok := c.pull()
+	if !ok {
+		return nil
+	}
+	c.push(row)
+
+	// build with the first row to count placeholders outside of the batch.
+	clone := c.clone([]any{row})
+	if _, _, err := clone.Build(); err != nil {
+		c.stop()
+		return err
+	}
+	width, err := c.b.rowWidth(row)
+	if err != nil {
+		c.stop()
+		return err
+	}
+	c.fixed = clone.Stats().Placeholders - width
+	return nil
+}
+
+// pull returns the next row.
+func (c *Chunks) pull() (any, bool) {
+	switch {
+	case c.hasRow:
+		c.hasRow = false
+		return c.pending, true
+	case c.source != nil:
+		row, ok := c.source.Next()
+		return row, ok
+	case len(c.rows) > 0:
+		row := c.rows[0]
+		c.rows = c.rows[1:]
+		return row, true
+	default:
+		return nil, false
+	}
+}
+
+// push returns the row back to be pulled again.
+func (c *Chunks) push(row any) {
+	c.pending, c.hasRow = row, true
+}
+
+func (c *Chunks) fail(err error) {
+	c.err = err
+	c.stop()
+}
+
+// stop the source, if it can be stopped.
+func (c *Chunks) stop() {
+	if s, ok := c.source.(interface{ Stop() }); ok {
+		s.Stop()
+	}
+}
+
+func (c *Chunks) build(rows []any) (string, []any, error) {
+	return c.clone(rows).Build()
+}
+
+// clone returns a copy of the builder with rows as the batch argument.
+func (c *Chunks) clone(rows []any) *Builder {
+	clone := *c.b
+	clone.args = append([][]any{}, c.b.args...)
+	clone.args[c.part] = append([]any{}, c.b.args[c.part]...)
+	clone.args[c.part][c.arg] = rows
+	return &clone
+}
+
+// findBatch finds the only `%#` verb, part and arg are its indexes.
+func (b *Builder) findBatch(part, arg *int) error {
+	found := false
+	for i, s := range b.parts {
+		for argID := 0; ; {
+			idx, err := b.indexVerb(s)
+			if err != nil {
+				return err
+			}
+			if idx == -1 {
+				break
+			}
+
+			s = s[idx+1:] // skip '%'
+			mod, verb, size, err := parseVerb(s)
+			if err != nil {
+				return err
+			}
+			s = s[size:]
+
+			if verb == '%' {
+				continue
+			}
+			if mod == '#' {
+				if found {
+					return fmt.Errorf("%w: found more than one", errChunkBatch)
+				}
+				found = true
+				*part, *arg = i, argID
+			}
+			argID++
+		}
+	}
+
+	switch {
+	case !found:
+		return fmt.Errorf("%w: found none", errChunkBatch)
+	case *arg >= len(b.args[*part]):
+		return fmt.Errorf("%w: have %d args, want %d", errTooFewArguments, len(b.args[*part]), *arg+1)
+	default:
+		return nil
+	}
+}
+
+// asRow returns values of a batch row: a slice or fields of a struct with `db` tags.
+func (b *Builder) asRow(v any) ([]any, error) {
+	row, err := structs.Row(v)
+	if errors.Is(err, structs.ErrNotRow) {
+		return nil, errNonSliceArgument
+	}
+	return row, err
+}
+
+func (b *Builder) rowWidth(row any) (int, error) {
+	values, err := b.asRow(row)
+	return len(values), err
+}
@@ -0,0 +1,134 @@
+package builq
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestChunks(t *testing.T) {
+	rows := [][]any{{1, "a"}, {2, "b"}, {3, "c"}, {4, "d"}, {5, "e"}}
+
+	var b Builder
+	b.Addf("INSERT INTO users (id, name) VALUES %#$", rows)
+	b.Addf("ON CONFLICT DO UPDATE SET source = %$", "import")
+
+	var queries []string
+	var args [][]any
+	chunks := b.Chunks(5)
+	for chunks.Next() {
+		query, arg := chunks.Query()
+		queries = append(queries, query)
+		args = append(args, arg)
+	}
+	if err := chunks.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	wantQueries := []string{
+		"INSERT INTO users (id, name) VALUES ($1, $2), ($3, $4)\nON CONFLICT DO UPDATE SET source = $5",
+		"INSERT INTO users (id, name) VALUES ($1, $2), ($3, $4)\nON CONFLICT DO UPDATE SET source = $5",
+		"INSERT INTO users (id, name) VALUES ($1, $2)\nON CONFLICT DO UPDATE SET source = $3",
+	}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Fatalf("\nhave: %q\nwant: %q", queries, wantQueries)
+	}
+	wantArgs := [][]any{
+		{1, "a", 2, "b", "import"},
+		{3, "c", 4, "d", "import"},
+		{5, "e", "import"},
+	}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("\nhave: %v\nwant: %v", args, wantArgs)
+	}
+
+	// the original builder isn't changed.
+	query, _, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "INSERT INTO users (id, name) VALUES ($1, $2), ($3, $4), ($5, $6), ($7, $8), ($9, $10)\nON CONFLICT DO UPDATE SET source = $11"
+	if query != want {
+		t.Fatalf("\nhave: %s\nwant: %s", query, want)
+	}
+}
+
+func TestChunksAfterBuild(t *testing.T) {
+	var b Builder
+	b.Addf("INSERT INTO t (a) VALUES %#$", [][]int{{1}, {2}, {3}})
+	if _, _, err := b.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	var queries []string
+	chunks := b.Chunks(2)
+	for chunks.Next() {
+		query, _ := chunks.Query()
+		queries = append(queries, query)
+	}
+	if err := chunks.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"INSERT INTO t (a) VALUES ($1), ($2)", "INSERT INTO t (a) VALUES ($1)"}
+	if !reflect.DeepEqual(queries, want) {
+		t.Fatalf("\nhave: %q\nwant: %q", queries, want)
+	}
+}
+
+func TestChunksStructs(t *testing.T) {
+	type model struct {
+		ID int `db:"id"`
+	}
+	type user struct {
+		model
+		Name    string `db:"name"`
+		Comment string
+		Hidden  string `db:"-"`
+	}
+	rows := []user{
+		{model{1}, "a", "x", "x"},
+		{model{2}, "b", "y", "y"},
+		{model{3}, "c", "z", "z"},
+	}
+
+	var b Builder
+	b.Addf("INSERT INTO users (id, name) VALUES %#?", rows)
+
+	var args [][]any
+	chunks := b.Chunks(LimitSQLiteLegacy)
+	for chunks.Next() {
+		query, arg := chunks.Query()
+		if want := "INSERT INTO users (id, name) VALUES (?, ?), (?, ?), (?, ?)"; query != want {
+			t.Fatalf("\nhave: %s\nwant: %s", query, want)
+		}
+		args = append(args, arg)
+	}
+	if err := chunks.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]any{{1, "a", 2, "b", 3, "c"}}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("\nhave: %v\nwant: %v", args, want)
+	}
+}
+
+func TestChunksErrors(t *testing.T) {
+	test := func(limit int, wantErr error, format string, args ...any) {
+		t.Helper()
+		var b Builder
+		b.Addf(constString(format), args...)
+		chunks := b.Chunks(limit)
+		for chunks.Next() {
+		}
+		if err := chunks.Err(); !errors.Is(err, wantErr) {
+			t.Fatalf("have %v, want %v", err, wantErr)
+		}
+	}
+
+	rows := [][]int{{1, 2}}
+	test(10, errChunkBatch, "INSERT INTO t VALUES (%+$)", []int{1})
+	test(10, errChunkBatch, "INSERT INTO t VALUES %#$, %#$", rows, rows)
+	test(2, errChunkLimit, "INSERT INTO t VALUES %#$ RETURNING %$", rows, 1)
+	test(10, errNonSliceArgument, "INSERT INTO t VALUES %#$", 1)
+	test(10, nil, "INSERT INTO t VALUES %#$", [][]int{})
+}
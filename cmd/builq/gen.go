@@ -0,0 +1,399 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cristalhq/builq"
+)
+
+// sqlQuery is an annotated query from a .sql file:
+//
+//	-- name: GetUser :one
+//	-- params: cols builq.Columns, id int64
+//	SELECT %s FROM users WHERE id = %$;
+type sqlQuery struct {
+	Name   string
+	Kind   string
+	Params []sqlParam
+	SQL    string
+	Line   int
+}
+
+// queryKinds maps a query kind to the suffix of its helper function:
+//
+//   - :one scans the first row with [builqsql.ScanOne],
+//   - :many scans all rows with [builqsql.ScanAll],
+//   - :exec executes the query with [builqsql.Exec].
+//
+// Without a kind only the builder function is generated.
+var queryKinds = map[string]string{
+	"one":  "One",
+	"many": "Many",
+	"exec": "Exec",
+}
+
+type sqlParam struct {
+	Name string
+	Type string
+}
+
+// sqlFile is a parsed .sql file.
+type sqlFile struct {
+	Name    string
+	Imports []string
+	Queries []sqlQuery
+}
+
+func runGen(args []string, w io.Writer) error {
+	fset := flag.NewFlagSet("gen", flag.ContinueOnError)
+	pkg := fset.String("pkg", os.Getenv("GOPACKAGE"), "package name, default is $GOPACKAGE or the directory name")
+	output := fset.String("o", "", "output file, default is <input>_builq.go for each input")
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "Usage: builq gen [flags] files.sql...\n\nFlags:\n")
+		fset.PrintDefaults()
+	}
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+
+	inputs := fset.Args()
+	if len(inputs) == 0 {
+		return errors.New("no input files")
+	}
+
+	var files []sqlFile
+	for _, input := range inputs {
+		file, err := parseSQLFile(input)
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+	}
+
+	if *pkg == "" {
+		dir := filepath.Dir(inputs[0])
+		if *output != "" {
+			dir = filepath.Dir(*output)
+		}
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		*pkg = filepath.Base(abs)
+	}
+
+	if *output != "" {
+		src, err := generate(*pkg, files)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(*output, src, 0o644)
+	}
+
+	for _, file := range files {
+		src, err := generate(*pkg, []sqlFile{file})
+		if err != nil {
+			return err
+		}
+		out := strings.TrimSuffix(file.Name, filepath.Ext(file.Name)) + "_builq.go"
+		if err := os.WriteFile(out, src, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\n", out)
+	}
+	return nil
+}
+
+func parseSQLFile(filename string) (sqlFile, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return sqlFile{}, err
+	}
+	defer f.Close()
+
+	file, err := parseSQL(f)
+	if err != nil {
+		return sqlFile{}, fmt.Errorf("%s: %w", filename, err)
+	}
+	file.Name = filename
+	return file, nil
+}
+
+// parseSQL parses queries annotated with `-- name: Name :kind` and optional `-- params: ...`.
+// Lines before the first query may have `-- import: path` annotations.
+func parseSQL(r io.Reader) (sqlFile, error) {
+	var file sqlFile
+	var body []string
+
+	flush := func() error {
+		if len(file.Queries) == 0 {
+			return nil
+		}
+		q := &file.Queries[len(file.Queries)-1]
+		q.SQL = strings.TrimSpace(strings.Join(body, "\n"))
+		body = body[:0]
+
+		if q.SQL == "" {
+			return fmt.Errorf("line %d: query %s is empty", q.Line, q.Name)
+		}
+		verbs, err := builq.Verbs(q.SQL, builq.LiteralVerbs)
+		if err != nil {
+			return fmt.Errorf("line %d: query %s: %w", q.Line, q.Name, err)
+		}
+		if q.Params == nil {
+			for i := range verbs {
+				q.Params = append(q.Params, sqlParam{Name: "arg" + strconv.Itoa(i+1), Type: "any"})
+			}
+		}
+		if len(q.Params) != len(verbs) {
+			return fmt.Errorf("line %d: query %s has %d verbs but %d params", q.Line, q.Name, len(verbs), len(q.Params))
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		key, value, ok := annotation(text)
+
+		switch {
+		case ok && key == "name":
+			if err := flush(); err != nil {
+				return sqlFile{}, err
+			}
+			q, err := parseName(value)
+			if err != nil {
+				return sqlFile{}, fmt.Errorf("line %d: %w", line, err)
+			}
+			q.Line = line
+			file.Queries = append(file.Queries, q)
+
+		case ok && key == "params" && len(file.Queries) > 0 && len(body) == 0:
+			params, err := parseParams(value)
+			if err != nil {
+				return sqlFile{}, fmt.Errorf("line %d: %w", line, err)
+			}
+			q := &file.Queries[len(file.Queries)-1]
+			q.Params = append(q.Params, params...)
+
+		case ok && key == "import" && len(file.Queries) == 0:
+			path, err := strconv.Unquote(value)
+			if err != nil {
+				path = value
+			}
+			file.Imports = append(file.Imports, path)
+
+		case len(file.Queries) > 0:
+			body = append(body, text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return sqlFile{}, err
+	}
+	if err := flush(); err != nil {
+		return sqlFile{}, err
+	}
+	return file, nil
+}
+
+// annotation parses lines like `-- key: value`.
+func annotation(line string) (key, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "--") {
+		return "", "", false
+	}
+	line = strings.TrimSpace(line[2:])
+	idx := strings.IndexByte(line, ':')
+	if idx == -1 {
+		return "", "", false
+	}
+	key = line[:idx]
+	switch key {
+	case "name", "params", "import":
+		return key, strings.TrimSpace(line[idx+1:]), true
+	default:
+		return "", "", false
+	}
+}
+
+func parseName(value string) (sqlQuery, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields) > 2 {
+		return sqlQuery{}, fmt.Errorf("invalid name annotation %q, want `-- name: Name :kind`", value)
+	}
+
+	q := sqlQuery{Name: fields[0]}
+	if len(fields) == 2 {
+		kind, ok := strings.CutPrefix(fields[1], ":")
+		if _, known := queryKinds[kind]; !ok || !known {
+			return sqlQuery{}, fmt.Errorf("invalid query kind %q, want :one, :many or :exec", fields[1])
+		}
+		q.Kind = kind
+	}
+
+	r, _ := utf8.DecodeRuneInString(q.Name)
+	if !token.IsIdentifier(q.Name) || !unicode.IsUpper(r) {
+		return sqlQuery{}, fmt.Errorf("query name %q must be an exported Go identifier", q.Name)
+	}
+	return q, nil
+}
+
+func parseParams(value string) ([]sqlParam, error) {
+	var params []sqlParam
+	for _, field := range splitParams(value) {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		name, typ, ok := strings.Cut(field, " ")
+		typ = strings.TrimSpace(typ)
+		if !ok || !token.IsIdentifier(name) || typ == "" {
+			return nil, fmt.Errorf("invalid param %q, want `name type`", field)
+		}
+		if name == "ctx" || name == "db" {
+			return nil, fmt.Errorf("param name %q is reserved for query helpers", name)
+		}
+		params = append(params, sqlParam{Name: name, Type: typ})
+	}
+	return params, nil
+}
+
+// splitParams splits by commas outside of brackets.
+func splitParams(s string) []string {
+	var res []string
+	var depth, last int
+	for i, c := range s {
+		switch c {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				res = append(res, s[last:i])
+				last = i + 1
+			}
+		}
+	}
+	return append(res, s[last:])
+}
+
+func generate(pkg string, files []sqlFile) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString("// Code generated by builq gen. DO NOT EDIT.\n")
+	for _, file := range files {
+		fmt.Fprintf(&buf, "// source: %s\n", filepath.ToSlash(file.Name))
+	}
+	fmt.Fprintf(&buf, "\npackage %s\n\nimport (\n", pkg)
+
+	imports := map[string]bool{builqPath: true}
+	fmt.Fprintf(&buf, "\t%q\n", builqPath)
+	for _, file := range files {
+		var kindImports []string
+		for _, q := range file.Queries {
+			switch q.Kind {
+			case "":
+			case "exec":
+				kindImports = append(kindImports, "context", "database/sql", builqPath+"/builqsql")
+			default:
+				kindImports = append(kindImports, "context", builqPath+"/builqsql")
+			}
+		}
+		for _, path := range append(kindImports, file.Imports...) {
+			if !imports[path] {
+				imports[path] = true
+				fmt.Fprintf(&buf, "\t%q\n", path)
+			}
+		}
+	}
+	buf.WriteString(")\n")
+
+	names := map[string]bool{}
+	for _, file := range files {
+		for _, q := range file.Queries {
+			if names[q.Name] {
+				return nil, fmt.Errorf("%s:%d: duplicate query %s", file.Name, q.Line, q.Name)
+			}
+			names[q.Name] = true
+			writeQueryFunc(&buf, q)
+		}
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated code is invalid: %w", err)
+	}
+	return src, nil
+}
+
+func writeQueryFunc(buf *bytes.Buffer, q sqlQuery) {
+	constName := strings.ToLower(q.Name[:1]) + q.Name[1:] + "Query"
+
+	fmt.Fprintf(buf, "\nconst %s = %s\n", constName, quoteSQL(q.SQL))
+
+	params := make([]string, len(q.Params))
+	names := make([]string, len(q.Params))
+	for i, p := range q.Params {
+		params[i] = p.Name + " " + p.Type
+		names[i] = p.Name
+	}
+
+	fmt.Fprintf(buf, "\n// %s returns a builder with the %s query.\n", q.Name, q.Name)
+	fmt.Fprintf(buf, "func %s(%s) *builq.Builder {\n", q.Name, strings.Join(params, ", "))
+	if len(names) == 0 {
+		fmt.Fprintf(buf, "\treturn new(builq.Builder).Addf(%s)\n", constName)
+	} else {
+		fmt.Fprintf(buf, "\treturn new(builq.Builder).Addf(%s, %s)\n", constName, strings.Join(names, ", "))
+	}
+	buf.WriteString("}\n")
+
+	if q.Kind == "" {
+		return
+	}
+
+	helper := q.Name + queryKinds[q.Kind]
+	params = append([]string{"ctx context.Context", "db builqsql.Querier"}, params...)
+	call := q.Name + "(" + strings.Join(names, ", ") + ")"
+
+	switch q.Kind {
+	case "one":
+		fmt.Fprintf(buf, "\n// %s executes the %s query and scans the first row into T.\n", helper, q.Name)
+		fmt.Fprintf(buf, "func %s[T any](%s) (T, error) {\n", helper, strings.Join(params, ", "))
+		fmt.Fprintf(buf, "\trows, err := builqsql.Query(ctx, db, %s)\n", call)
+		buf.WriteString("\tif err != nil {\n\t\tvar zero T\n\t\treturn zero, err\n\t}\n")
+		buf.WriteString("\treturn builqsql.ScanOne[T](rows)\n")
+	case "many":
+		fmt.Fprintf(buf, "\n// %s executes the %s query and scans all rows into T.\n", helper, q.Name)
+		fmt.Fprintf(buf, "func %s[T any](%s) ([]T, error) {\n", helper, strings.Join(params, ", "))
+		fmt.Fprintf(buf, "\trows, err := builqsql.Query(ctx, db, %s)\n", call)
+		buf.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+		buf.WriteString("\treturn builqsql.ScanAll[T](rows)\n")
+	case "exec":
+		fmt.Fprintf(buf, "\n// %s executes the %s query.\n", helper, q.Name)
+		fmt.Fprintf(buf, "func %s(%s) (sql.Result, error) {\n", helper, strings.Join(params, ", "))
+		fmt.Fprintf(buf, "\treturn builqsql.Exec(ctx, db, %s)\n", call)
+	}
+	buf.WriteString("}\n")
+}
+
+// quoteSQL returns a raw string literal when possible to keep the query reviewable.
+func quoteSQL(s string) string {
+	if !strings.Contains(s, "`") && !strings.Contains(s, "\r") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGen(t *testing.T) {
+	file, err := parseSQLFile("testdata/gen/queries.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have, err := generate("queries", []sqlFile{file})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const golden = "testdata/gen/queries_builq.go.golden"
+	if os.Getenv("UPDATE_GOLDEN") != "" {
+		if err := os.WriteFile(golden, have, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(have, want) {
+		t.Fatalf("\nhave:\n%s\nwant:\n%s", have, want)
+	}
+}
+
+func TestRunGen(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "users.sql")
+	if err := os.WriteFile(input, []byte("-- name: CountUsers :one\nSELECT count(*) FROM users;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := run([]string{"gen", "-pkg", "db", input}, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, "users_builq.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), "func CountUsers() *builq.Builder {") {
+		t.Fatalf("unexpected output:\n%s", src)
+	}
+}
+
+func TestParseSQLErrors(t *testing.T) {
+	test := func(src, wantErr string) {
+		t.Helper()
+		_, err := parseSQL(strings.NewReader(src))
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("have %v, want %q", err, wantErr)
+		}
+	}
+
+	test("-- name: getUser\nSELECT 1", "must be an exported Go identifier")
+	test("-- name: GetUser one\nSELECT 1", "invalid query kind")
+	test("-- name: GetUser :first\nSELECT 1", "invalid query kind")
+	test("-- name: GetUser :one\n-- params: db string\nSELECT %$", "is reserved")
+	test("-- name: GetUser\n", "is empty")
+	test("-- name: GetUser\nSELECT %x", "unsupported verb")
+	test("-- name: GetUser\n-- params: id int\nSELECT 1", "has 0 verbs but 1 params")
+	test("-- name: GetUser\n-- params: int\nSELECT %$", "invalid param")
+}
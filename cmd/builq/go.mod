@@ -0,0 +1,11 @@
+module github.com/cristalhq/builq/cmd/builq
+
+go 1.22.0
+
+require github.com/cristalhq/builq v0.0.0-20261019063417-9a2ad3502674
+
+require (
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/tools v0.26.0
+)
@@ -0,0 +1,628 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cristalhq/builq"
+	"golang.org/x/tools/go/packages"
+)
+
+const builqPath = "github.com/cristalhq/builq"
+
+// maxSequences per builder, conditional branches can make too many of them.
+const maxSequences = 64
+
+// Query is a possible sequence of builder parts within a function.
+//
+// Placeholders is the number of placeholders, like "2". Verbs with `+ # e A U` modifiers
+// write as many placeholders as their arguments require, so the count gets a `+` suffix,
+// like "2+", which reads as 2 plus a variable number.
+type Query struct {
+	Func         string   `json:"func"`
+	Builder      string   `json:"builder"`
+	Parts        []Part   `json:"parts"`
+	Verbs        []string `json:"verbs"`
+	Placeholders string   `json:"placeholders"`
+	Truncated    bool     `json:"truncated,omitempty"`
+}
+
+// Part is a single Addf, BuildFn or Q call.
+type Part struct {
+	Pos    string   `json:"pos"`
+	Format string   `json:"format"`
+	Verbs  []string `json:"verbs,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+var literalModes = map[string]builq.LiteralMode{
+	"verbs": builq.LiteralVerbs,
+	"text":  builq.LiteralText,
+	"error": builq.LiteralError,
+}
+
+func runInventory(args []string, w io.Writer) error {
+	fset := flag.NewFlagSet("inventory", flag.ContinueOnError)
+	format := fset.String("format", "json", "output format: json or markdown")
+	tests := fset.Bool("tests", false, "include _test.go files")
+	literals := fset.String("literals", "verbs", "literal mode of the builders, see builq.Literals: verbs, text or error")
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "Usage: builq inventory [flags] [dirs, use ./... for recursive]\n\nFlags:\n")
+		fset.PrintDefaults()
+	}
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+
+	mode, ok := literalModes[*literals]
+	if !ok {
+		return fmt.Errorf("unknown literal mode %q", *literals)
+	}
+
+	patterns := fset.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	queries, err := inventory(patterns, *tests, mode)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(queries)
+	case "markdown", "md":
+		return writeMarkdown(w, queries)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
+
+// inventory finds all builq queries in packages from directories matched by patterns.
+// Calls are resolved with type information, so only builq builders are reported.
+func inventory(patterns []string, tests bool, mode builq.LiteralMode) ([]Query, error) {
+	dirs := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		// patterns are directories, not import paths.
+		if !filepath.IsAbs(pattern) && !strings.HasPrefix(pattern, ".") {
+			pattern = "./" + pattern
+		}
+		dirs[i] = pattern
+	}
+
+	cfg := &packages.Config{
+		Mode:  packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Tests: tests,
+	}
+	pkgs, err := packages.Load(cfg, dirs...)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].ID < pkgs[j].ID
+	})
+
+	queries := []Query{}
+	seen := map[string]bool{} // files are shared by a package and its test variant.
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("package %s: %v", pkg.PkgPath, pkg.Errors[0])
+		}
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue // generated test main.
+		}
+		queries = append(queries, inspectPackage(pkg, seen, mode)...)
+	}
+	return queries, nil
+}
+
+func inspectPackage(pkg *packages.Package, seen map[string]bool, mode builq.LiteralMode) []Query {
+	files := make([]*ast.File, 0, len(pkg.Syntax))
+	for _, file := range pkg.Syntax {
+		name := pkg.Fset.Position(file.Pos()).Filename
+		if !seen[name] {
+			seen[name] = true
+			files = append(files, file)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return pkg.Fset.Position(files[i].Pos()).Filename < pkg.Fset.Position(files[j].Pos()).Filename
+	})
+
+	var queries []Query
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			in := &inspector{
+				fset: pkg.Fset,
+				info: pkg.TypesInfo,
+				mode: mode,
+			}
+			queries = append(queries, in.inspectFunc(funcName(fn), fn.Body)...)
+		}
+	}
+	return queries
+}
+
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	return "(" + typeString(fn.Recv.List[0].Type) + ")." + fn.Name.Name
+}
+
+func typeString(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.StarExpr:
+		return "*" + typeString(expr.X)
+	case *ast.SelectorExpr:
+		return typeString(expr.X) + "." + expr.Sel.Name
+	case *ast.IndexExpr:
+		return typeString(expr.X)
+	case *ast.IndexListExpr:
+		return typeString(expr.X)
+	default:
+		return "?"
+	}
+}
+
+// isBuilq reports whether obj is declared in the builq package.
+func isBuilq(obj types.Object) bool {
+	return obj != nil && obj.Pkg() != nil && obj.Pkg().Path() == builqPath
+}
+
+// isBuildFn reports whether typ is builq.BuildFn.
+func isBuildFn(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	return ok && isBuilq(named.Obj()) && named.Obj().Name() == "BuildFn"
+}
+
+// sequence of parts indexes.
+type sequence []int
+
+// state holds possible sequences for each builder.
+type state map[string][]sequence
+
+func (s state) clone() state {
+	res := make(state, len(s))
+	for k, v := range s {
+		res[k] = append([]sequence(nil), v...)
+	}
+	return res
+}
+
+type inspector struct {
+	fset *token.FileSet
+	info *types.Info
+	mode builq.LiteralMode
+
+	fn        string
+	parts     []Part
+	order     []string // builders in order of appearance.
+	truncated map[string]bool
+	queries   []Query
+	lits      int // function literals counter.
+}
+
+func (in *inspector) inspectFunc(name string, body *ast.BlockStmt) []Query {
+	in.fn = name
+	in.truncated = map[string]bool{}
+	st := in.block(body.List, state{})
+
+	var queries []Query
+	for _, key := range in.order {
+		for _, seq := range st[key] {
+			if len(seq) > 0 {
+				queries = append(queries, in.query(key, seq))
+			}
+		}
+	}
+	return append(queries, in.queries...)
+}
+
+func (in *inspector) query(builder string, seq sequence) Query {
+	q := Query{
+		Func:      in.fn,
+		Builder:   builder,
+		Parts:     make([]Part, 0, len(seq)),
+		Verbs:     []string{},
+		Truncated: in.truncated[builder],
+	}
+	for _, idx := range seq {
+		part := in.parts[idx]
+		q.Parts = append(q.Parts, part)
+		q.Verbs = append(q.Verbs, part.Verbs...)
+	}
+	q.Placeholders = countPlaceholders(q.Verbs)
+	return q
+}
+
+// countPlaceholders counts placeholders of verbs, see [Query].
+func countPlaceholders(verbs []string) string {
+	var n int
+	var variable bool
+	for _, verb := range verbs {
+		switch {
+		case verb == "%s" || verb == "%d":
+		case len(verb) > 2 && strings.IndexByte("+#eAU", verb[1]) != -1:
+			variable = true
+		default:
+			n++
+		}
+	}
+	if variable {
+		return strconv.Itoa(n) + "+"
+	}
+	return strconv.Itoa(n)
+}
+
+func (in *inspector) block(stmts []ast.Stmt, st state) state {
+	for _, stmt := range stmts {
+		st = in.stmt(stmt, st)
+	}
+	return st
+}
+
+func (in *inspector) stmt(stmt ast.Stmt, st state) state {
+	switch stmt := stmt.(type) {
+	case *ast.BlockStmt:
+		return in.block(stmt.List, st)
+
+	case *ast.IfStmt:
+		if stmt.Init != nil {
+			st = in.stmt(stmt.Init, st)
+		}
+		st = in.calls(stmt.Cond, st)
+		then := in.block(stmt.Body.List, st.clone())
+		other := st
+		if stmt.Else != nil {
+			other = in.stmt(stmt.Else, st.clone())
+		}
+		return in.merge(then, other)
+
+	case *ast.ForStmt:
+		if stmt.Init != nil {
+			st = in.stmt(stmt.Init, st)
+		}
+		return in.merge(st, in.block(stmt.Body.List, st.clone()))
+
+	case *ast.RangeStmt:
+		st = in.calls(stmt.X, st)
+		return in.merge(st, in.block(stmt.Body.List, st.clone()))
+
+	case *ast.SwitchStmt:
+		if stmt.Init != nil {
+			st = in.stmt(stmt.Init, st)
+		}
+		return in.clauses(stmt.Body, st)
+
+	case *ast.TypeSwitchStmt:
+		if stmt.Init != nil {
+			st = in.stmt(stmt.Init, st)
+		}
+		return in.clauses(stmt.Body, st)
+
+	case *ast.SelectStmt:
+		return in.clauses(stmt.Body, st)
+
+	case *ast.LabeledStmt:
+		return in.stmt(stmt.Stmt, st)
+
+	default:
+		return in.calls(stmt, st)
+	}
+}
+
+// clauses handles switch and select clauses, each of them is a branch.
+func (in *inspector) clauses(body *ast.BlockStmt, st state) state {
+	var res state
+	hasDefault := false
+	for _, clause := range body.List {
+		var stmts []ast.Stmt
+		switch clause := clause.(type) {
+		case *ast.CaseClause:
+			hasDefault = hasDefault || clause.List == nil
+			stmts = clause.Body
+		case *ast.CommClause:
+			hasDefault = hasDefault || clause.Comm == nil
+			stmts = clause.Body
+		}
+		branch := in.block(stmts, st.clone())
+		if res == nil {
+			res = branch
+		} else {
+			res = in.merge(res, branch)
+		}
+	}
+	switch {
+	case res == nil:
+		return st
+	case !hasDefault:
+		return in.merge(res, st)
+	default:
+		return res
+	}
+}
+
+// merge returns a union of sequences from both branches.
+// Builder which isn't used in a branch has an empty sequence there.
+func (in *inspector) merge(a, b state) state {
+	res := state{}
+	for key := range a {
+		res[key] = nil
+	}
+	for key := range b {
+		res[key] = nil
+	}
+	for key := range res {
+		seqsA, ok := a[key]
+		if !ok {
+			seqsA = []sequence{{}}
+		}
+		seqsB, ok := b[key]
+		if !ok {
+			seqsB = []sequence{{}}
+		}
+
+		seen := map[string]bool{}
+		for _, seq := range append(append([]sequence(nil), seqsA...), seqsB...) {
+			id := fmt.Sprint(seq)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if len(res[key]) == maxSequences {
+				in.truncated[key] = true
+				break
+			}
+			res[key] = append(res[key], seq)
+		}
+	}
+	return res
+}
+
+// calls finds builq calls in node in source order and appends them to the state.
+func (in *inspector) calls(node ast.Node, st state) state {
+	if node == nil {
+		return st
+	}
+
+	var calls []*ast.CallExpr
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			calls = append(calls, n)
+		case *ast.FuncLit:
+			in.funcLit(n)
+			return false
+		}
+		return true
+	})
+	sort.Slice(calls, func(i, j int) bool {
+		return calls[i].Lparen < calls[j].Lparen
+	})
+
+	for _, call := range calls {
+		key, isQ, ok := in.builderOf(call)
+		if !ok || len(call.Args) == 0 {
+			continue
+		}
+
+		idx := in.addPart(call)
+		if isQ {
+			in.queries = append(in.queries, in.query("builq.Q", sequence{idx}))
+			continue
+		}
+
+		if _, ok := st[key]; !ok {
+			in.order = append(in.order, key)
+			st[key] = []sequence{{}}
+		}
+		for i, seq := range st[key] {
+			st[key][i] = append(seq[:len(seq):len(seq)], idx)
+		}
+	}
+	return st
+}
+
+// funcLit inspects a function literal as a separate function.
+func (in *inspector) funcLit(lit *ast.FuncLit) {
+	in.lits++
+	sub := &inspector{
+		fset: in.fset,
+		info: in.info,
+		mode: in.mode,
+	}
+	name := in.fn + ".func" + strconv.Itoa(in.lits)
+	in.queries = append(in.queries, sub.inspectFunc(name, lit.Body)...)
+}
+
+// builderOf returns a builder key for a call of Addf, BuildFn or builq.Q.
+func (in *inspector) builderOf(call *ast.CallExpr) (key string, isQ, ok bool) {
+	var id *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return "", false, false
+	}
+
+	switch obj := in.info.Uses[id].(type) {
+	case *types.Func:
+		if !isBuilq(obj) {
+			return "", false, false
+		}
+		sig := obj.Type().(*types.Signature)
+		switch {
+		case sig.Recv() == nil && obj.Name() == "Q":
+			return "", true, true
+		case sig.Recv() != nil && obj.Name() == "Addf":
+			return in.receiver(call.Fun.(*ast.SelectorExpr).X), false, true
+		}
+	case *types.Var:
+		if isBuildFn(obj.Type()) {
+			return exprString(call.Fun), false, true
+		}
+	}
+	return "", false, false
+}
+
+// receiver returns the root of the Addf chain like `b` in `b.Addf(...).Addf(...)`.
+func (in *inspector) receiver(expr ast.Expr) string {
+	for {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			break
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			break
+		}
+		expr = sel.X
+	}
+	return exprString(expr)
+}
+
+// constString returns a value of a constant string expression.
+func (in *inspector) constString(expr ast.Expr) (string, bool) {
+	tv, ok := in.info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+func (in *inspector) addPart(call *ast.CallExpr) int {
+	pos := in.fset.Position(call.Pos())
+	part := Part{Pos: fmt.Sprintf("%s:%d", relPath(pos.Filename), pos.Line)}
+
+	format, ok := in.constString(call.Args[0])
+	if !ok {
+		part.Format = exprString(call.Args[0])
+		part.Error = "format is not a constant string"
+	} else {
+		part.Format = format
+		verbs, err := builq.Verbs(format, in.mode)
+		if err != nil {
+			part.Error = err.Error()
+		}
+		part.Verbs = verbs
+	}
+
+	in.parts = append(in.parts, part)
+	return len(in.parts) - 1
+}
+
+// relPath returns path relative to the working directory when possible.
+func relPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
+
+func exprString(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.SelectorExpr:
+		return exprString(expr.X) + "." + expr.Sel.Name
+	case *ast.StarExpr:
+		return "*" + exprString(expr.X)
+	case *ast.UnaryExpr:
+		return expr.Op.String() + exprString(expr.X)
+	case *ast.ParenExpr:
+		return exprString(expr.X)
+	case *ast.IndexExpr:
+		return exprString(expr.X) + "[" + exprString(expr.Index) + "]"
+	case *ast.BasicLit:
+		return expr.Value
+	case *ast.CallExpr:
+		return exprString(expr.Fun) + "(...)"
+	default:
+		return "?"
+	}
+}
+
+func writeMarkdown(w io.Writer, queries []Query) error {
+	var sb strings.Builder
+	sb.WriteString("# builq inventory\n")
+
+	for i, q := range queries {
+		fmt.Fprintf(&sb, "\n## %d. %s", i+1, q.Func)
+		if q.Builder != "builq.Q" {
+			fmt.Fprintf(&sb, " (builder `%s`)", q.Builder)
+		}
+		sb.WriteString("\n\n")
+
+		var code strings.Builder
+		for _, part := range q.Parts {
+			code.WriteString(part.Format)
+			code.WriteByte('\n')
+		}
+		fence := codeFence(code.String())
+		sb.WriteString(fence + "sql\n")
+		sb.WriteString(code.String())
+		sb.WriteString(fence + "\n\n")
+
+		sb.WriteString("| Position | Verbs | Error |\n")
+		sb.WriteString("| --- | --- | --- |\n")
+		for _, part := range q.Parts {
+			fmt.Fprintf(&sb, "| %s | %s | %s |\n", tableCell(part.Pos), tableCell(strings.Join(part.Verbs, " ")), tableCell(part.Error))
+		}
+		fmt.Fprintf(&sb, "\nPlaceholders: %s\n", q.Placeholders)
+		if q.Truncated {
+			sb.WriteString("\nToo many branches, some sequences are omitted.\n")
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// codeFence returns a backtick fence longer than any backtick run in code,
+// so queries with backticks (MySQL identifiers, Go raw strings) don't close the block.
+func codeFence(code string) string {
+	longest, run := 0, 0
+	for i := 0; i < len(code); i++ {
+		if code[i] != '`' {
+			run = 0
+			continue
+		}
+		run++
+		longest = max(longest, run)
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// tableCell escapes `|` and newlines which break a markdown table row.
+func tableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
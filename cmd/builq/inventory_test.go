@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cristalhq/builq"
+)
+
+func TestInventory(t *testing.T) {
+	queries, err := inventory([]string{"testdata/inventory"}, false, builq.LiteralVerbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var have []string
+	for _, q := range queries {
+		var formats []string
+		for _, part := range q.Parts {
+			formats = append(formats, part.Format)
+		}
+		have = append(have, q.Func+" "+q.Builder+": "+strings.Join(formats, " | "))
+	}
+
+	want := []string{
+		"Users b: SELECT %s FROM users | WHERE id = %$ | LIMIT %d | OFFSET %$",
+		"Users b: SELECT %s FROM users | WHERE active IS TRUE | LIMIT %d | OFFSET %$",
+		"Users b: SELECT %s FROM users | LIMIT %d | OFFSET %$",
+		"Orders q: SELECT * FROM orders WHERE id IN (%+$) | AND single",
+		"Orders q: SELECT * FROM orders WHERE id IN (%+$) | AND pair",
+		"Orders q: SELECT * FROM orders WHERE id IN (%+$)",
+		"Filter q: AND name = %$",
+		"Count builq.Q: SELECT count(*) FROM users WHERE age > %$",
+		"Count builq.Q: SELECT %x",
+		"(*repo).Like r.q: WHERE name LIKE 'a%' AND id = %$",
+	}
+	if strings.Join(have, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("\nhave:\n%s\nwant:\n%s", strings.Join(have, "\n"), strings.Join(want, "\n"))
+	}
+
+	first := queries[0]
+	if first.Placeholders != "2" || strings.Join(first.Verbs, " ") != "%s %$ %d %$" {
+		t.Fatalf("have %s placeholders, verbs %v", first.Placeholders, first.Verbs)
+	}
+	if orders := queries[3]; orders.Placeholders != "0+" {
+		t.Fatalf("have %s placeholders for %%+$", orders.Placeholders)
+	}
+	if pos := first.Parts[1].Pos; !strings.HasSuffix(pos, "queries.go:17") {
+		t.Fatalf("have pos %s", pos)
+	}
+	if bad := queries[len(queries)-2]; bad.Parts[0].Error == "" {
+		t.Fatal("want verb error")
+	}
+	if like := queries[len(queries)-1]; like.Parts[0].Error == "" {
+		t.Fatal("want verb error for '%' in a literal")
+	}
+}
+
+func TestInventoryLiterals(t *testing.T) {
+	queries, err := inventory([]string{"testdata/inventory"}, false, builq.LiteralText)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	like := queries[len(queries)-1]
+	if like.Parts[0].Error != "" || strings.Join(like.Verbs, " ") != "%$" {
+		t.Fatalf("have verbs %v, error %q", like.Verbs, like.Parts[0].Error)
+	}
+}
+
+func TestRunInventory(t *testing.T) {
+	var buf bytes.Buffer
+	if err := run([]string{"inventory", "./..."}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var queries []Query
+	if err := json.Unmarshal(buf.Bytes(), &queries); err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) != 0 {
+		t.Fatalf("testdata must be skipped, have %d queries", len(queries))
+	}
+
+	buf.Reset()
+	if err := run([]string{"inventory", "-format", "markdown", "testdata/inventory"}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "## 1. Users (builder `b`)") {
+		t.Fatalf("unexpected markdown:\n%s", buf.String())
+	}
+
+	if err := run([]string{"inventory", "-literals", "unknown"}, &buf); err == nil {
+		t.Fatal("want literal mode error")
+	}
+	if err := run([]string{"unknown"}, &buf); err == nil {
+		t.Fatal("want error")
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	queries := []Query{{
+		Func:    "Users",
+		Builder: "b",
+		Parts: []Part{
+			{Pos: "users.go:10", Format: "SELECT `id` FROM users WHERE id = %$ ```", Verbs: []string{"%$"}},
+			{Pos: "users.go:11", Format: "AND a || b = %x", Error: "unsupported verb |x|"},
+		},
+		Placeholders: "1",
+	}}
+
+	var buf bytes.Buffer
+	if err := writeMarkdown(&buf, queries); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# builq inventory\n\n## 1. Users (builder `b`)\n\n" +
+		"````sql\nSELECT `id` FROM users WHERE id = %$ ```\nAND a || b = %x\n````\n\n" +
+		"| Position | Verbs | Error |\n| --- | --- | --- |\n" +
+		"| users.go:10 | %$ |  |\n" +
+		"| users.go:11 |  | unsupported verb \\|x\\| |\n" +
+		"\nPlaceholders: 1\n"
+	if have := buf.String(); have != want {
+		t.Fatalf("\nhave:\n%s\nwant:\n%s", have, want)
+	}
+}
+
+func TestCountPlaceholders(t *testing.T) {
+	test := func(verbs []string, want string) {
+		t.Helper()
+		if have := countPlaceholders(verbs); have != want {
+			t.Errorf("%v: have %s, want %s", verbs, have, want)
+		}
+	}
+
+	test(nil, "0")
+	test([]string{"%s", "%$", "%d", "%?"}, "2")
+	test([]string{"%$", "%+$"}, "1+")
+	for _, verb := range []string{"%#?", "%e$", "%A$", "%U$"} {
+		test([]string{verb}, "0+")
+	}
+}
@@ -0,0 +1,55 @@
+// Command builq provides tools for builq users.
+//
+// Usage:
+//
+//	builq <command> [flags] [arguments]
+//
+// Commands:
+//
+//	inventory  list every builq query in Go packages
+//	gen        generate Go functions from annotated .sql files
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "builq: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("command is required, see 'builq help'")
+	}
+
+	switch cmd, args := args[0], args[1:]; cmd {
+	case "inventory":
+		return runInventory(args, w)
+	case "gen":
+		return runGen(args, w)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(w, usage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q, see 'builq help'", cmd)
+	}
+}
+
+const usage = `Usage:
+
+	builq <command> [flags] [arguments]
+
+Commands:
+
+	inventory  list every builq query in Go packages
+	gen        generate Go functions from annotated .sql files
+
+Run 'builq <command> -h' for command flags.
+`
@@ -0,0 +1,19 @@
+-- import: time
+
+-- name: GetUser :one
+-- params: cols builq.Columns, id int64
+SELECT %s FROM users
+WHERE id = %$;
+
+-- name: ListUsers :many
+-- params: ids []int64,
+-- params: since time.Time
+SELECT * FROM users -- all columns
+WHERE id IN (%+$) AND created_at > %$
+ORDER BY id;
+
+-- name: DeleteAll :exec
+DELETE FROM users WHERE name LIKE 'a%%' AND age > %d;
+
+-- name: Ping
+SELECT 1;
@@ -0,0 +1,61 @@
+package inventory
+
+import (
+	bq "github.com/cristalhq/builq"
+)
+
+const (
+	selectUsers = "SELECT %s FROM users"
+	byID        = "WHERE id = %$"
+	limit       = "LIMIT " + "%d"
+)
+
+func Users(id int, active bool) {
+	var b bq.Builder
+	b.Addf(selectUsers, bq.Columns{"id", "name"})
+	if id > 0 {
+		b.Addf(byID, id)
+	} else if active {
+		b.Addf("WHERE active IS TRUE")
+	}
+	b.Addf(limit, 10).Addf("OFFSET %$", 0)
+}
+
+func Orders(ids []int) {
+	q := bq.New()
+	q("SELECT * FROM orders WHERE id IN (%+$)", ids)
+	switch len(ids) {
+	case 1:
+		q("AND single")
+	case 2:
+		q("AND pair")
+	}
+}
+
+func Filter(q bq.BuildFn, names []string) {
+	for range names {
+		q("AND name = %$", "")
+	}
+}
+
+func Count() {
+	bq.Q("SELECT count(*) FROM users WHERE age > %$", 18)
+	bq.Q("SELECT %x")
+}
+
+type logger struct{}
+
+func (logger) Addf(format string, args ...any) {}
+
+func Unrelated() {
+	var l logger
+	l.Addf("not a query %d", 1)
+}
+
+type repo struct {
+	q bq.BuildFn
+}
+
+func (r *repo) Like() {
+	r.q("WHERE name LIKE 'a%' AND id = %$", 1)
+}
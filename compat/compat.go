@@ -0,0 +1,206 @@
+// Package compat provides adapters between builq and squirrel/sqlx ecosystems.
+//
+// The package doesn't import squirrel or sqlx, it only mirrors their contracts.
+package compat
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cristalhq/builq"
+)
+
+// Sqlizer is the squirrel's Sqlizer interface.
+type Sqlizer interface {
+	ToSql() (string, []any, error)
+}
+
+// Builder is implemented by [*builq.Builder], [*builq.OnelineBuilder] and [builq.BuildFn].
+type Builder interface {
+	Build() (query string, args []any, err error)
+}
+
+// ToSql returns a squirrel's Sqlizer which builds b.
+func ToSql(b Builder) Sqlizer {
+	return sqlizer{b: b}
+}
+
+type sqlizer struct {
+	b Builder
+}
+
+func (s sqlizer) ToSql() (string, []any, error) {
+	return s.b.Build()
+}
+
+// Expr returns a [builq.Expr] from a squirrel's Sqlizer.
+// Sqlizer must use question placeholders (squirrel's default),
+// they will be rendered with placeholders of the builder and renumbered.
+// Question marks inside literals and comments are kept, `??`, `?|` and `?&`
+// return an error as in [builq.SplitPlaceholders].
+func Expr(s Sqlizer) builq.Expr {
+	return sqlizerExpr{s: s}
+}
+
+type sqlizerExpr struct {
+	s Sqlizer
+}
+
+func (e sqlizerExpr) WriteSQL(w *builq.Writer) error {
+	query, args, err := e.s.ToSql()
+	if err != nil {
+		return err
+	}
+
+	parts, err := builq.SplitPlaceholders(query, builq.StyleQuestion)
+	if err != nil {
+		return err
+	}
+	if len(parts)-1 != len(args) {
+		return fmt.Errorf("%w: have %d args, expected %d", errArgsMismatch, len(args), len(parts)-1)
+	}
+
+	for i, part := range parts {
+		if i > 0 {
+			if err := w.WriteArg(args[i-1]); err != nil {
+				return err
+			}
+		}
+		w.WriteString(part)
+	}
+	return nil
+}
+
+// Bind types, same as in sqlx.
+const (
+	UNKNOWN = iota
+	QUESTION
+	DOLLAR
+	NAMED
+	AT
+)
+
+// BindType returns the bind type for a given database driver name, same as sqlx.BindType.
+func BindType(driverName string) int {
+	switch driverName {
+	case "postgres", "pgx", "pgx/v4", "pgx/v5", "pq-timeouts", "cloudsqlpostgres", "ql", "nrpostgres", "cockroach":
+		return DOLLAR
+	case "mysql", "sqlite3", "sqlite", "nrmysql", "nrsqlite3":
+		return QUESTION
+	case "oci8", "ora", "goracle", "godror":
+		return NAMED
+	case "sqlserver", "azuresql":
+		return AT
+	default:
+		return UNKNOWN
+	}
+}
+
+// Rebind a query from question placeholders to the given bind type, same as sqlx.Rebind.
+// Question marks inside literals and comments are kept, see [builq.Rebind].
+// An ambiguous query (like one with `??` operator) is returned as is.
+func Rebind(bindType int, query string) string {
+	switch bindType {
+	case DOLLAR:
+		return rebind(query, builq.StyleDollar)
+	case AT:
+		return rebind(query, builq.StyleAt)
+	case NAMED:
+		// sqlx uses `:argN` which isn't a builq style.
+		parts, err := builq.SplitPlaceholders(query, builq.StyleQuestion)
+		if err != nil {
+			return query
+		}
+
+		var sb strings.Builder
+		sb.Grow(len(query) + 10)
+
+		for i, part := range parts {
+			if i > 0 {
+				sb.WriteString(":arg")
+				sb.WriteString(strconv.Itoa(i))
+			}
+			sb.WriteString(part)
+		}
+		return sb.String()
+	default:
+		return query
+	}
+}
+
+func rebind(query string, style builq.Style) string {
+	res, err := builq.Rebind(query, builq.StyleQuestion, style)
+	if err != nil {
+		return query
+	}
+	return res
+}
+
+// In expands slice arguments into `?, ?, ?` lists, same as sqlx.In.
+// Query must use question placeholders, like builq `%?` output.
+// []byte and [driver.Valuer] arguments are not expanded.
+func In(query string, args ...any) (string, []any, error) {
+	parts, err := builq.SplitPlaceholders(query, builq.StyleQuestion)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(parts)-1 != len(args) {
+		return "", nil, fmt.Errorf("%w: have %d args, expected %d", errArgsMismatch, len(args), len(parts)-1)
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(query) + 10)
+	resArgs := make([]any, 0, len(args))
+
+	for i, part := range parts {
+		if i > 0 {
+			arg := args[i-1]
+
+			if !isExpandable(arg) {
+				sb.WriteByte('?')
+				resArgs = append(resArgs, arg)
+			} else {
+				v := reflect.ValueOf(arg)
+				if v.Len() == 0 {
+					return "", nil, errEmptySlice
+				}
+				for j := 0; j < v.Len(); j++ {
+					if j > 0 {
+						sb.WriteString(", ")
+					}
+					sb.WriteByte('?')
+					resArgs = append(resArgs, v.Index(j).Interface())
+				}
+			}
+		}
+		sb.WriteString(part)
+	}
+	return sb.String(), resArgs, nil
+}
+
+func isExpandable(arg any) bool {
+	if _, ok := arg.(driverValuer); ok {
+		return false
+	}
+	v := reflect.ValueOf(arg)
+	if v.Kind() != reflect.Slice {
+		return false
+	}
+	return v.Type().Elem().Kind() != reflect.Uint8
+}
+
+// driverValuer is the same as driver.Valuer.
+type driverValuer interface {
+	Value() (any, error)
+}
+
+var (
+	// errArgsMismatch when number of placeholders and arguments differ.
+	errArgsMismatch = errors.New("number of placeholders and arguments mismatch")
+
+	// errEmptySlice when an empty slice is passed to [In].
+	errEmptySlice = errors.New("empty slice passed to In")
+)
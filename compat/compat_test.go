@@ -0,0 +1,116 @@
+package compat
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cristalhq/builq"
+)
+
+// squirrelLike mimics squirrel's builders with question placeholders.
+type squirrelLike struct {
+	query string
+	args  []any
+}
+
+func (s squirrelLike) ToSql() (string, []any, error) {
+	return s.query, s.args, nil
+}
+
+func TestToSql(t *testing.T) {
+	var b builq.Builder
+	b.Addf("SELECT * FROM users WHERE id = %$", 42)
+
+	var s Sqlizer = ToSql(&b)
+	query, args, err := s.ToSql()
+	mustEqual(t, err, nil)
+	mustEqual(t, query, "SELECT * FROM users WHERE id = $1")
+	mustEqual(t, args, []any{42})
+
+	q := builq.New()
+	q("SELECT 1")
+	query, _, err = ToSql(q).ToSql()
+	mustEqual(t, err, nil)
+	mustEqual(t, query, "SELECT 1")
+}
+
+func TestExpr(t *testing.T) {
+	sq := squirrelLike{
+		query: "(name = ? OR note = 'why?' OR \"col?\" = $$?$$ /* ? */) AND age > ? -- why?\n",
+		args:  []any{"john", 18},
+	}
+
+	var b builq.Builder
+	b.Addf("SELECT * FROM users WHERE id = %$", 42)
+	b.Addf("AND %e$", Expr(sq))
+
+	query, args, err := b.Build()
+	mustEqual(t, err, nil)
+	mustEqual(t, query, "SELECT * FROM users WHERE id = $1\nAND (name = $2 OR note = 'why?' OR \"col?\" = $$?$$ /* ? */) AND age > $3 -- why?")
+	mustEqual(t, args, []any{42, "john", 18})
+
+	var b2 builq.Builder
+	b2.Addf("WHERE %e?", Expr(squirrelLike{query: "a = ?"}))
+	_, _, err = b2.Build()
+	if !errors.Is(err, errArgsMismatch) {
+		t.Fatalf("have %v, want %v", err, errArgsMismatch)
+	}
+
+	var b3 builq.Builder
+	b3.Addf("WHERE %e?", Expr(squirrelLike{query: "data ?? 'key'"}))
+	if _, _, err = b3.Build(); err == nil {
+		t.Fatal("want error for ?? operator")
+	}
+}
+
+func TestRebind(t *testing.T) {
+	const query = "SELECT * FROM t WHERE a = ? AND b = '?' AND c IN (?, ?)"
+
+	mustEqual(t, Rebind(QUESTION, query), query)
+	mustEqual(t, Rebind(DOLLAR, query), "SELECT * FROM t WHERE a = $1 AND b = '?' AND c IN ($2, $3)")
+	mustEqual(t, Rebind(NAMED, query), "SELECT * FROM t WHERE a = :arg1 AND b = '?' AND c IN (:arg2, :arg3)")
+	mustEqual(t, Rebind(AT, query), "SELECT * FROM t WHERE a = @p1 AND b = '?' AND c IN (@p2, @p3)")
+
+	const literals = `SELECT "a?" FROM t WHERE b = $$?$$ /* ? */ AND c = ? -- ?`
+	mustEqual(t, Rebind(DOLLAR, literals), `SELECT "a?" FROM t WHERE b = $$?$$ /* ? */ AND c = $1 -- ?`)
+	mustEqual(t, Rebind(NAMED, literals), `SELECT "a?" FROM t WHERE b = $$?$$ /* ? */ AND c = :arg1 -- ?`)
+
+	const operator = "SELECT * FROM t WHERE data ?? 'key' AND a = ?"
+	mustEqual(t, Rebind(DOLLAR, operator), operator)
+	mustEqual(t, Rebind(NAMED, operator), operator)
+
+	mustEqual(t, BindType("pgx"), DOLLAR)
+	mustEqual(t, BindType("sqlserver"), AT)
+	mustEqual(t, BindType("unknown"), UNKNOWN)
+}
+
+func TestIn(t *testing.T) {
+	var b builq.Builder
+	b.Addf("SELECT * FROM t WHERE id IN (%?) AND name = %? AND data = %?", []int{1, 2, 3}, "john", []byte("raw"))
+
+	query, args, err := b.Build()
+	mustEqual(t, err, nil)
+
+	query, args, err = In(query, args...)
+	mustEqual(t, err, nil)
+	mustEqual(t, query, "SELECT * FROM t WHERE id IN (?, ?, ?) AND name = ? AND data = ?")
+	mustEqual(t, args, []any{1, 2, 3, "john", []byte("raw")})
+
+	_, _, err = In("SELECT * FROM t WHERE id IN (?)", []int{})
+	if !errors.Is(err, errEmptySlice) {
+		t.Fatalf("have %v, want %v", err, errEmptySlice)
+	}
+
+	_, _, err = In("SELECT * FROM t WHERE id = ?")
+	if !errors.Is(err, errArgsMismatch) {
+		t.Fatalf("have %v, want %v", err, errArgsMismatch)
+	}
+}
+
+func mustEqual(t testing.TB, have, want any) {
+	t.Helper()
+	if !reflect.DeepEqual(have, want) {
+		t.Fatalf("\nhave: %+v\nwant: %+v", have, want)
+	}
+}
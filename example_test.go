@@ -118,6 +118,23 @@ func ExampleOnelineBuilder() {
 	// SELECT foo, bar FROM table WHERE id = $1
 }
 
+func ExampleOnelineBuilder_multiline() {
+	var b builq.OnelineBuilder
+	b.Addf(`SELECT id, name -- legacy columns
+		FROM users`)
+	b.Addf("WHERE id = %$", 123)
+
+	query, _, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(query)
+
+	// Output:
+	// SELECT id, name /* legacy columns */ FROM users WHERE id = $1
+}
+
 func ExampleBuilder_DebugBuild() {
 	cols := builq.Columns{"foo", "bar"}
 
@@ -192,6 +209,30 @@ func Example_queryWithPercent() {
 	// [42]
 }
 
+func ExampleBuilder_Literals() {
+	var b builq.Builder
+	b.Literals(builq.LiteralText)
+	b.Addf("SELECT * FROM users WHERE name LIKE '%$' -- 100%sure")
+	b.Addf("AND id = %$", 42)
+
+	query, args, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("query:")
+	fmt.Println(query)
+	fmt.Println("args:")
+	fmt.Println(args)
+
+	// Output:
+	// query:
+	// SELECT * FROM users WHERE name LIKE '%$' -- 100%sure
+	// AND id = $1
+	// args:
+	// [42]
+}
+
 func Example_query1() {
 	cols := builq.Columns{"foo, bar"}
 
@@ -543,3 +584,130 @@ func Example_sliceInBatch() {
 	// args:
 	// [42 [1 2 3] 69 [4 5 6]]
 }
+
+type boundingBox struct {
+	minX, minY, maxX, maxY float64
+}
+
+func (bb boundingBox) WriteSQL(w *builq.Writer) error {
+	w.WriteString("geom && ST_MakeEnvelope(")
+	if err := w.WriteArgs(bb.minX, bb.minY, bb.maxX, bb.maxY); err != nil {
+		return err
+	}
+	w.WriteString(")")
+	return nil
+}
+
+func ExampleExpr() {
+	bbox := boundingBox{minX: 1, minY: 2, maxX: 3, maxY: 4}
+
+	var b builq.Builder
+	b.Addf("SELECT * FROM places")
+	b.Addf("WHERE kind = %$ AND active = %$", "cafe", true)
+	b.Addf("AND %e$", bbox)
+
+	query, args, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("query:")
+	fmt.Println(query)
+	fmt.Println("args:")
+	fmt.Println(args)
+	fmt.Println("debug:")
+	fmt.Println(b.DebugBuild())
+
+	// Output:
+	// query:
+	// SELECT * FROM places
+	// WHERE kind = $1 AND active = $2
+	// AND geom && ST_MakeEnvelope($3, $4, $5, $6)
+	// args:
+	// [cafe true 1 2 3 4]
+	// debug:
+	// SELECT * FROM places
+	// WHERE kind = 'cafe' AND active = 'true'
+	// AND geom && ST_MakeEnvelope(1, 2, 3, 4)
+}
+
+func ExampleBuilder_BuildPretty() {
+	var b builq.Builder
+	b.Addf("SELECT id, name FROM users")
+	b.Addf("WHERE active AND id IN (SELECT user_id FROM orders WHERE total > %$)", 100)
+	b.Addf("ORDER BY name")
+
+	query, args, err := b.BuildPretty(builq.FormatOptions{KeywordCase: builq.KeywordUpper})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("query:")
+	fmt.Println(query)
+	fmt.Println("args:")
+	fmt.Println(args)
+
+	// Output:
+	// query:
+	// SELECT id, name
+	// FROM users
+	// WHERE active
+	//   AND id IN (
+	//     SELECT user_id
+	//     FROM orders
+	//     WHERE total > $1
+	//   )
+	// ORDER BY name
+	// args:
+	// [100]
+}
+
+func ExampleBuilder_Tag() {
+	var b builq.Builder
+	b.Addf("SELECT * FROM users WHERE id = %$;", 42)
+	b.Tag("route", "/users/{id}")
+	b.Tag("controller", "users")
+
+	query, _, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(query)
+
+	// Output:
+	// SELECT * FROM users WHERE id = $1 /*controller='users',route='%2Fusers%2F%7Bid%7D'*/;
+}
+
+func ExampleBuilder_Fingerprint() {
+	var b builq.Builder
+	b.Addf("SELECT * FROM users")
+	b.Addf("WHERE id IN (%+$) AND age > %d", []int{1, 2, 3}, 18)
+
+	shape, hash, err := b.Fingerprint()
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(shape)
+	fmt.Printf("%x\n", hash)
+
+	// Output:
+	// SELECT * FROM users WHERE id IN (?...) AND age > ?
+	// 4521f8482f06f52f
+}
+
+func ExampleRebind() {
+	const legacy = "SELECT * FROM users WHERE name = ? AND note <> 'why?' -- by id?\nAND id IN (?, ?)"
+
+	query, err := builq.Rebind(legacy, builq.StyleQuestion, builq.StyleDollar)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(query)
+
+	// Output:
+	// SELECT * FROM users WHERE name = $1 AND note <> 'why?' -- by id?
+	// AND id IN ($2, $3)
+}
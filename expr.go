@@ -0,0 +1,51 @@
+package builq
+
+import "strings"
+
+// Expr is a custom SQL expression that renders both SQL and its arguments.
+//
+// Expr must be passed via the `%e` modifier followed by a placeholder verb,
+// like `%e$`, `%e?` or `%e@`. The placeholder verb defines which placeholders
+// [Writer.WriteArg] emits.
+type Expr interface {
+	WriteSQL(w *Writer) error
+}
+
+// Writer is passed to [Expr.WriteSQL] to render an expression.
+// Placeholders written via Writer share style and counter with the [Builder].
+type Writer struct {
+	b       *Builder
+	sb      *strings.Builder
+	resArgs *[]any
+	verb    byte
+}
+
+// WriteString writes s to the query as is.
+// Same as `%s` verb it must be used with care, no user input should be passed here.
+func (w *Writer) WriteString(s string) {
+	w.sb.WriteString(s)
+}
+
+// WriteArg writes a placeholder for arg and appends arg to the query arguments.
+// In [Builder.DebugBuild] the argument is written instead of the placeholder.
+func (w *Writer) WriteArg(arg any) error {
+	return w.b.writeArg(w.sb, w.resArgs, w.verb, arg)
+}
+
+// WriteArgs works as WriteArg but writes comma separated placeholders.
+func (w *Writer) WriteArgs(args ...any) error {
+	for i, arg := range args {
+		if i > 0 {
+			w.sb.WriteString(", ")
+		}
+		if err := w.WriteArg(arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteExpr writes a nested expression.
+func (w *Writer) WriteExpr(expr Expr) error {
+	return expr.WriteSQL(w)
+}
@@ -0,0 +1,92 @@
+package builq
+
+import (
+	"hash/fnv"
+	"strings"
+)
+
+// Fingerprint returns a canonical shape of the query and its 64-bit FNV-1a hash.
+//
+// Shape is computed from the constant formats and verbs only, arguments don't affect it:
+//   - `%$`, `%?`, `%@` and `%d` become `?`,
+//   - `%+$` becomes `?...` whatever the slice length is,
+//   - `%#$` becomes `(?...)...`,
+//   - `%e$` and `%s` are kept as `%e` and `%s`,
+//   - whitespace is collapsed into a single space.
+//
+// Arguments of `%s` and `%e` aren't a part of the shape, so the shape doesn't
+// guard what is written through them. Tags added via [Builder.Tag] are not a part of the shape.
+//
+// The result is cached until the next Addf.
+func (b *Builder) Fingerprint() (shape string, hash uint64, err error) {
+	if b.shape != nil {
+		return b.shape.shape, b.shape.hash, nil
+	}
+
+	var sb strings.Builder
+
+	for _, format := range b.parts {
+		format = normalizeFormat(format)
+		if format == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		if err := b.writeShape(&sb, format); err != nil {
+			return "", 0, err
+		}
+	}
+
+	shape = sb.String()
+	b.shape = &fingerprint{shape: shape, hash: shapeHash(shape)}
+	return b.shape.shape, b.shape.hash, nil
+}
+
+type fingerprint struct {
+	shape string
+	hash  uint64
+}
+
+func shapeHash(shape string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(shape))
+	return h.Sum64()
+}
+
+// writeShape writes the shape of a single format.
+func (b *Builder) writeShape(sb *strings.Builder, s string) error {
+	for {
+		idx, err := b.indexVerb(s)
+		if err != nil {
+			return err
+		}
+		if idx == -1 {
+			sb.WriteString(s)
+			return nil
+		}
+		sb.WriteString(s[:idx])
+
+		s = s[idx+1:] // skip '%'
+		mod, verb, size, err := parseVerb(s)
+		if err != nil {
+			return err
+		}
+		s = s[size:]
+
+		switch {
+		case verb == '%':
+			sb.WriteByte('%')
+		case verb == 's':
+			sb.WriteString("%s")
+		case mod == '+':
+			sb.WriteString("?...")
+		case mod == '#':
+			sb.WriteString("(?...)...")
+		case mod == 'e':
+			sb.WriteString("%e")
+		default:
+			sb.WriteByte('?')
+		}
+	}
+}
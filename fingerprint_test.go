@@ -0,0 +1,86 @@
+package builq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	var b1 Builder
+	b1.Addf("SELECT * FROM %s", "users")
+	b1.Addf("WHERE id IN (%+$) AND age > %d", []int{1, 2, 3}, 18)
+	b1.Addf("LIMIT %$", 10)
+
+	var b2 OnelineBuilder
+	b2.Addf("SELECT *   FROM %s", "orders")
+	b2.Addf("WHERE id IN (%+$)\n  AND age > %d", []int{1}, 21)
+	b2.Addf("LIMIT %$", 100)
+	b2.Tag("route", "/users")
+
+	const want = "SELECT * FROM %s WHERE id IN (?...) AND age > ? LIMIT ?"
+
+	shape1, hash1, err := b1.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	shape2, hash2, err := b2.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if shape1 != want || shape2 != want {
+		t.Fatalf("\nhave: %s\nhave: %s\nwant: %s", shape1, shape2, want)
+	}
+	if hash1 != hash2 {
+		t.Fatalf("hashes must be equal: %x != %x", hash1, hash2)
+	}
+
+	var b3 Builder
+	b3.Addf("INSERT INTO t VALUES %#?", [][]any{{1, 2}})
+	b3.Addf("-- comment\nRETURNING %e? %% 2", nil)
+	shape3, hash3, err := b3.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "INSERT INTO t VALUES (?...)... /* comment */ RETURNING %e % 2"; shape3 != want {
+		t.Fatalf("\nhave: %s\nwant: %s", shape3, want)
+	}
+	if hash3 == hash1 {
+		t.Fatal("hashes must differ")
+	}
+
+	var b4 Builder
+	b4.Addf("SELECT %x")
+	if _, _, err := b4.Fingerprint(); !errors.Is(err, errUnsupportedVerb) {
+		t.Fatalf("have %v, want %v", err, errUnsupportedVerb)
+	}
+}
+
+func TestFingerprintCache(t *testing.T) {
+	var b Builder
+	b.Addf("SELECT * FROM t WHERE a = '%$'")
+
+	shape, _, err := b.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT * FROM t WHERE a = '?'"; shape != want {
+		t.Fatalf("\nhave: %s\nwant: %s", shape, want)
+	}
+
+	b.parts[0] = "changed behind the cache"
+	if cached, _, _ := b.Fingerprint(); cached != shape {
+		t.Fatalf("want cached shape, have %s", cached)
+	}
+
+	b.parts[0] = "SELECT * FROM t WHERE a = '%$'"
+	b.Literals(LiteralText)
+	if shape, _, _ := b.Fingerprint(); shape != "SELECT * FROM t WHERE a = '%$'" {
+		t.Fatalf("literal mode must reset the cache, have %s", shape)
+	}
+
+	b.Addf("LIMIT %d", 10)
+	if shape, _, _ := b.Fingerprint(); shape != "SELECT * FROM t WHERE a = '%$' LIMIT ?" {
+		t.Fatalf("Addf must reset the cache, have %s", shape)
+	}
+}
@@ -0,0 +1,346 @@
+package builq
+
+import "strings"
+
+// FormatOptions for [Format] and [Builder.BuildPretty].
+type FormatOptions struct {
+	// Indent is the number of spaces per nesting level. Default is 2.
+	Indent int
+
+	// Width is the line width after which lists are split into a line per item. Default is 80.
+	Width int
+
+	// KeywordCase changes case of SQL keywords. Default is [KeywordAsIs].
+	KeywordCase KeywordCase
+}
+
+// KeywordCase defines how [Format] changes case of SQL keywords.
+type KeywordCase byte
+
+const (
+	// KeywordAsIs keeps keywords as they are.
+	KeywordAsIs KeywordCase = iota
+
+	// KeywordUpper writes keywords in upper case.
+	KeywordUpper
+
+	// KeywordLower writes keywords in lower case.
+	KeywordLower
+)
+
+// BuildPretty works as [Builder.Build] but formats the query with [Format].
+func (b *Builder) BuildPretty(opts FormatOptions) (query string, args []any, err error) {
+	query, args, err = b.build()
+	if err != nil {
+		return "", nil, err
+	}
+	query, err = Format(query, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	return query, args, nil
+}
+
+// Format re-indents SQL query: every clause (SELECT, FROM, WHERE, JOIN, etc.)
+// starts on a new line, subqueries are indented and long lists are split.
+// Literals, comments and placeholders are kept untouched.
+func Format(query string, opts FormatOptions) (string, error) {
+	if opts.Indent <= 0 {
+		opts.Indent = 2
+	}
+	if opts.Width <= 0 {
+		opts.Width = 80
+	}
+
+	tokens, err := tokenize(query)
+	if err != nil {
+		return "", err
+	}
+
+	f := &formatter{
+		opts:      opts,
+		tokens:    tokens,
+		frames:    []formatFrame{{}},
+		lineStart: true,
+	}
+	f.format()
+	return f.sb.String(), nil
+}
+
+type token struct {
+	kind  tokenKind
+	text  string
+	space bool // whitespace before the token in the source.
+}
+
+// tokenize splits query into literals, comments, words and punctuation, whitespace is dropped.
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	var space bool
+	for i := 0; i < len(s); {
+		if isSpace(s[i]) {
+			space = true
+			i++
+			continue
+		}
+
+		kind, end, err := literalAt(s, i)
+		if err != nil {
+			return nil, err
+		}
+		if kind == tokenText {
+			end = i + 1
+			if s[i] == '$' || s[i] == '@' || isIdentChar(s[i]) {
+				for end < len(s) && (isIdentChar(s[end]) || s[end] == '@') {
+					end++
+				}
+			}
+		}
+
+		tokens = append(tokens, token{kind: kind, text: s[i:end], space: space})
+		space = false
+		i = end
+	}
+	return tokens, nil
+}
+
+// formatFrame is a state of a parenthesis level.
+type formatFrame struct {
+	indent    int
+	outer     int    // indent of the line with the opening parenthesis.
+	subquery  bool   // parenthesis with a subquery.
+	clause    string // current clause keyword.
+	breakList bool   // split current clause list by commas.
+	between   bool   // BETWEEN is waiting for its AND.
+	cases     int    // CASE depth.
+}
+
+type formatter struct {
+	opts      FormatOptions
+	tokens    []token
+	frames    []formatFrame
+	sb        strings.Builder
+	lineStart bool
+	indent    int
+}
+
+func (f *formatter) format() {
+	for i := 0; i < len(f.tokens); i++ {
+		tok := f.tokens[i]
+		frame := &f.frames[len(f.frames)-1]
+
+		switch {
+		case tok.kind == tokenLineComment:
+			f.write(tok.text, tok.space)
+			f.newline(frame.indent + f.listIndent(frame))
+
+		case tok.kind != tokenText:
+			f.write(tok.text, tok.space)
+
+		case tok.text == "(":
+			f.write(tok.text, tok.space)
+			next := f.wordAt(i + 1)
+			sub := next == "SELECT" || next == "WITH"
+			indent := f.indent
+			if sub {
+				indent++
+			}
+			f.frames = append(f.frames, formatFrame{indent: indent, outer: f.indent, subquery: sub})
+
+		case tok.text == ")":
+			if len(f.frames) > 1 {
+				f.frames = f.frames[:len(f.frames)-1]
+				if frame.subquery {
+					f.newline(frame.outer)
+				}
+			}
+			f.write(tok.text, tok.space)
+
+		case tok.text == ",":
+			f.write(tok.text, tok.space)
+			if frame.breakList {
+				f.newline(frame.indent + 1)
+			}
+
+		case tok.text == ";":
+			f.write(tok.text, tok.space)
+			frame.clause, frame.breakList, frame.between, frame.cases = "", false, false, 0
+			f.newline(frame.indent)
+
+		default:
+			i = f.formatWord(i, frame)
+		}
+	}
+}
+
+// formatWord writes a word at tokens[i] and returns index of the last written token.
+func (f *formatter) formatWord(i int, frame *formatFrame) int {
+	tok := f.tokens[i]
+	word := strings.ToUpper(tok.text)
+
+	switch word {
+	case "CASE":
+		frame.cases++
+	case "END":
+		if frame.cases > 0 {
+			frame.cases--
+		}
+	case "BETWEEN":
+		frame.between = true
+	case "AND", "OR":
+		switch {
+		case word == "AND" && frame.between:
+			frame.between = false
+		case frame.cases == 0 && (frame.clause == "WHERE" || frame.clause == "HAVING"):
+			f.newline(frame.indent + 1)
+		}
+	}
+
+	var n int
+	if len(f.frames) == 1 || frame.subquery {
+		n = f.clauseAt(i)
+	}
+	if n == 0 {
+		f.write(f.keyword(tok.text), tok.space)
+		return i
+	}
+
+	f.newline(frame.indent)
+	for j := i; j < i+n; j++ {
+		f.write(f.keyword(f.tokens[j].text), f.tokens[j].space)
+	}
+
+	frame.clause = word
+	frame.between = false
+	frame.breakList = f.clauseWidth(i+n) > f.opts.Width
+	if frame.breakList {
+		f.newline(frame.indent + 1)
+	}
+	return i + n - 1
+}
+
+// clauseAt returns the number of words in a clause keyword at tokens[i] or 0.
+func (f *formatter) clauseAt(i int) int {
+	switch word := f.wordAt(i); word {
+	case "SELECT", "FROM", "WHERE", "HAVING", "LIMIT", "OFFSET", "VALUES", "SET",
+		"RETURNING", "INSERT", "DELETE", "JOIN", "INTERSECT", "EXCEPT":
+		return 1
+	case "UNION":
+		if f.wordAt(i+1) == "ALL" {
+			return 2
+		}
+		return 1
+	case "WITH":
+		if f.wordAt(i+1) != "TIME" {
+			return 1
+		}
+	case "UPDATE":
+		if f.wordAt(i-1) != "DO" {
+			return 1
+		}
+	case "GROUP", "ORDER", "PARTITION":
+		if f.wordAt(i+1) == "BY" {
+			return 2
+		}
+	case "ON":
+		if f.wordAt(i+1) == "CONFLICT" {
+			return 2
+		}
+	case "LEFT", "RIGHT", "FULL", "INNER", "CROSS":
+		n := 1
+		if f.wordAt(i+1) == "OUTER" {
+			n++
+		}
+		if f.wordAt(i+n) == "JOIN" {
+			return n + 1
+		}
+	}
+	return 0
+}
+
+// clauseWidth returns the width of clause content from tokens[i] till the next clause.
+func (f *formatter) clauseWidth(i int) int {
+	var width, depth int
+	for ; i < len(f.tokens); i++ {
+		tok := f.tokens[i]
+		if tok.kind == tokenText {
+			switch {
+			case tok.text == "(":
+				depth++
+			case tok.text == ")" || tok.text == ";":
+				if depth == 0 {
+					return width
+				}
+				depth--
+			case depth == 0 && f.clauseAt(i) > 0:
+				return width
+			}
+		}
+		width += len(tok.text)
+		if tok.space {
+			width++
+		}
+	}
+	return width
+}
+
+// listIndent returns extra indent for the content of a split list.
+func (f *formatter) listIndent(frame *formatFrame) int {
+	if frame.breakList {
+		return 1
+	}
+	return 0
+}
+
+// wordAt returns upper-cased word at tokens[i] or empty string.
+func (f *formatter) wordAt(i int) string {
+	if i < 0 || i >= len(f.tokens) || f.tokens[i].kind != tokenText {
+		return ""
+	}
+	return strings.ToUpper(f.tokens[i].text)
+}
+
+func (f *formatter) keyword(word string) string {
+	if _, ok := sqlKeywords[strings.ToUpper(word)]; !ok {
+		return word
+	}
+	switch f.opts.KeywordCase {
+	case KeywordUpper:
+		return strings.ToUpper(word)
+	case KeywordLower:
+		return strings.ToLower(word)
+	default:
+		return word
+	}
+}
+
+func (f *formatter) write(s string, space bool) {
+	switch {
+	case f.lineStart:
+		f.sb.WriteString(strings.Repeat(" ", f.indent*f.opts.Indent))
+		f.lineStart = false
+	case space:
+		f.sb.WriteByte(' ')
+	}
+	f.sb.WriteString(s)
+}
+
+func (f *formatter) newline(indent int) {
+	if !f.lineStart {
+		f.sb.WriteByte('\n')
+		f.lineStart = true
+	}
+	f.indent = indent
+}
+
+var sqlKeywords = map[string]struct{}{}
+
+func init() {
+	for _, kw := range strings.Fields(`ALL AND ANY AS ASC BETWEEN BY CASE CONFLICT CROSS
+		DELETE DESC DISTINCT DO ELSE END EXCEPT EXISTS FALSE FROM FULL GROUP HAVING
+		ILIKE IN INNER INSERT INTERSECT INTO IS JOIN LATERAL LEFT LIKE LIMIT
+		NOT NOTHING NULL OFFSET ON OR ORDER OUTER OVER PARTITION RECURSIVE RETURNING
+		RIGHT SELECT SET THEN TRUE UNION UPDATE USING VALUES WHEN WHERE WITH`) {
+		sqlKeywords[kw] = struct{}{}
+	}
+}
@@ -0,0 +1,44 @@
+package builq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	test := func(opts FormatOptions, query, want string) {
+		t.Helper()
+		have, err := Format(query, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have != want {
+			t.Errorf("\nhave:\n%s\nwant:\n%s", have, want)
+		}
+	}
+
+	test(FormatOptions{},
+		"SELECT a, b FROM t WHERE a = $1 AND b BETWEEN 1 AND 2 OR c IN (SELECT id FROM t2 WHERE x = 'a -- b') ORDER BY a",
+		"SELECT a, b\nFROM t\nWHERE a = $1\n  AND b BETWEEN 1 AND 2\n  OR c IN (\n    SELECT id\n    FROM t2\n    WHERE x = 'a -- b'\n  )\nORDER BY a")
+
+	test(FormatOptions{KeywordCase: KeywordUpper, Indent: 4},
+		"with x as (select 1) select * from x left outer join y on x.a = y.a -- from here\nwhere extract(year from ts) = ?",
+		"WITH x AS (\n    SELECT 1\n)\nSELECT *\nFROM x\nLEFT OUTER JOIN y ON x.a = y.a -- from here\nWHERE extract(year FROM ts) = ?")
+
+	test(FormatOptions{KeywordCase: KeywordLower, Width: 40},
+		"SELECT first_column, second_column, third_column FROM t GROUP BY 1",
+		"select\n  first_column,\n  second_column,\n  third_column\nfrom t\ngroup by 1")
+
+	test(FormatOptions{},
+		"INSERT INTO t (a, b) VALUES (@p1, @p2) ON CONFLICT (a) DO UPDATE SET b = excluded.b RETURNING id; SELECT 1",
+		"INSERT INTO t (a, b)\nVALUES (@p1, @p2)\nON CONFLICT (a) DO UPDATE\nSET b = excluded.b\nRETURNING id;\nSELECT 1")
+
+	test(FormatOptions{},
+		"SELECT CASE WHEN a AND b THEN 1 END, ts::timestamp with time zone\nFROM t\nWHERE a = :1 AND f = $$ SELECT 1 $$",
+		"SELECT CASE WHEN a AND b THEN 1 END, ts::timestamp with time zone\nFROM t\nWHERE a = :1\n  AND f = $$ SELECT 1 $$")
+
+	_, err := Format("SELECT 'abc", FormatOptions{})
+	if !errors.Is(err, errUnterminatedLiteral) {
+		t.Fatalf("have %v, want %v", err, errUnterminatedLiteral)
+	}
+}
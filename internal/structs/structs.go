@@ -0,0 +1,105 @@
+// Package structs maps struct fields to columns by `db` tags.
+//
+// The same rules are used for scanning, batch rows and COPY rows:
+// fields without a tag or with `db:"-"` are skipped, embedded structs without a tag are flattened.
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrNotRow when a value is neither a slice nor a struct.
+var ErrNotRow = errors.New("not a slice or struct")
+
+// Field is a struct field with a `db` tag.
+type Field struct {
+	Name  string
+	Index []int
+}
+
+var fieldsCache sync.Map // map[reflect.Type][]Field
+
+// Fields returns fields of a struct type in declaration order.
+func Fields(typ reflect.Type) ([]Field, error) {
+	if fields, ok := fieldsCache.Load(typ); ok {
+		return fields.([]Field), nil
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s isn't a struct", typ)
+	}
+
+	var fields []Field
+	seen := map[string]bool{}
+	if err := appendFields(&fields, seen, typ, nil); err != nil {
+		return nil, err
+	}
+	fieldsCache.Store(typ, fields)
+	return fields, nil
+}
+
+func appendFields(fields *[]Field, seen map[string]bool, typ reflect.Type, parent []int) error {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		index := append(append([]int{}, parent...), i)
+
+		tag, ok := f.Tag.Lookup("db")
+		if tag == "-" {
+			continue
+		}
+		if !ok {
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				if err := appendFields(fields, seen, f.Type, index); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !f.IsExported() {
+			return fmt.Errorf("field %s.%s with db tag isn't exported", typ, f.Name)
+		}
+		if seen[tag] {
+			return fmt.Errorf("duplicate db tag %q in %s", tag, typ)
+		}
+		seen[tag] = true
+		*fields = append(*fields, Field{Name: tag, Index: index})
+	}
+	return nil
+}
+
+// Row returns values of a row: elements of a slice or fields of a struct (see [Fields]).
+// Pointers and interfaces are dereferenced.
+func Row(v any) ([]any, error) {
+	value := reflect.ValueOf(v)
+	for (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		row := make([]any, value.Len())
+		for i := range row {
+			row[i] = value.Index(i).Interface()
+		}
+		return row, nil
+
+	case reflect.Struct:
+		fields, err := Fields(value.Type())
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("%s has no fields with db tag", value.Type())
+		}
+		row := make([]any, len(fields))
+		for i, f := range fields {
+			row[i] = value.FieldByIndex(f.Index).Interface()
+		}
+		return row, nil
+
+	default:
+		return nil, ErrNotRow
+	}
+}
@@ -0,0 +1,62 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testModel struct {
+	ID int `db:"id"`
+}
+
+type testRow struct {
+	testModel
+	Name    string `db:"name"`
+	Comment string
+	Hidden  string `db:"-"`
+}
+
+func TestFields(t *testing.T) {
+	fields, err := Fields(reflect.TypeOf(testRow{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Field{{Name: "id", Index: []int{0, 0}}, {Name: "name", Index: []int{1}}}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("\nhave: %+v\nwant: %+v", fields, want)
+	}
+
+	type dup struct {
+		A int `db:"a"`
+		B int `db:"a"`
+	}
+	if _, err := Fields(reflect.TypeOf(dup{})); err == nil {
+		t.Fatal("must fail")
+	}
+}
+
+func TestRow(t *testing.T) {
+	test := func(v any, want []any) {
+		t.Helper()
+		row, err := Row(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(row, want) {
+			t.Fatalf("\nhave: %v\nwant: %v", row, want)
+		}
+	}
+
+	test([]any{1, "a"}, []any{1, "a"})
+	test([2]int{1, 2}, []any{1, 2})
+	test(testRow{testModel{1}, "a", "b", "c"}, []any{1, "a"})
+	test(&testRow{testModel{2}, "b", "", ""}, []any{2, "b"})
+
+	if _, err := Row(1); !errors.Is(err, ErrNotRow) {
+		t.Fatalf("have %v, want %v", err, ErrNotRow)
+	}
+	if _, err := Row(struct{ A int }{1}); err == nil {
+		t.Fatal("must fail")
+	}
+}
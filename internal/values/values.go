@@ -0,0 +1,102 @@
+// Package values resolves arguments and encodes them in PostgreSQL text format.
+//
+// The same rules are used for array literals, unnest columns and COPY rows.
+package values
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ErrUnsupported when a value has no text format.
+var ErrUnsupported = errors.New("unsupported value")
+
+// TimeFormat is accepted by PostgreSQL for both timestamp and timestamptz.
+const TimeFormat = "2006-01-02 15:04:05.999999-07:00"
+
+// Resolve calls [driver.Valuer] and dereferences pointers and interfaces.
+// isNull is true for nil pointers and interfaces, nil []byte and nil from a Valuer,
+// the returned value still has the type of a nil pointer or []byte.
+func Resolve(v reflect.Value) (res reflect.Value, isNull bool, err error) {
+	for {
+		if !v.IsValid() {
+			return v, true, nil
+		}
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Interface:
+			if v.IsNil() {
+				return v, true, nil
+			}
+		case reflect.Slice:
+			if v.IsNil() && v.Type().Elem().Kind() == reflect.Uint8 {
+				return v, true, nil
+			}
+		}
+
+		if valuer, ok := v.Interface().(driver.Valuer); ok {
+			value, err := valuer.Value()
+			if err != nil {
+				return v, false, err
+			}
+			v = reflect.ValueOf(value)
+			continue
+		}
+
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Interface:
+			v = v.Elem()
+		default:
+			return v, false, nil
+		}
+	}
+}
+
+// Text returns a resolved value in PostgreSQL text format.
+// bare is true for booleans and numbers, they never need quoting.
+func Text(v reflect.Value) (s string, bare bool, err error) {
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.Format(TimeFormat), false, nil
+	case []byte:
+		return `\x` + hex.EncodeToString(value), false, nil
+	case fmt.Stringer:
+		return value.String(), false, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return "t", true, nil
+		}
+		return "f", true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true, nil
+	case reflect.Float32, reflect.Float64:
+		return formatFloat(v.Float(), v.Type().Bits()), true, nil
+	case reflect.String:
+		return v.String(), false, nil
+	default:
+		return "", false, fmt.Errorf("%w: %s", ErrUnsupported, v.Type())
+	}
+}
+
+func formatFloat(f float64, bits int) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, bits)
+	}
+}
@@ -0,0 +1,67 @@
+package values
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestResolve(t *testing.T) {
+	one := 1
+	var nilBytes []byte
+
+	test := func(v any, want any, wantNull bool) {
+		t.Helper()
+		res, isNull, err := Resolve(reflect.ValueOf(v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if isNull != wantNull {
+			t.Fatalf("%#v: have null %v, want %v", v, isNull, wantNull)
+		}
+		if !isNull && !reflect.DeepEqual(res.Interface(), want) {
+			t.Fatalf("%#v: have %#v, want %#v", v, res.Interface(), want)
+		}
+	}
+
+	test(nil, nil, true)
+	test(1, 1, false)
+	test(&one, 1, false)
+	test((*int)(nil), nil, true)
+	test(nilBytes, nil, true)
+	test([]byte{}, []byte{}, false)
+	test(sql.NullString{String: "a", Valid: true}, "a", false)
+	test(sql.NullString{}, nil, true)
+	test(&sql.NullInt64{Int64: 2, Valid: true}, int64(2), false)
+	test((*sql.NullInt64)(nil), nil, true)
+}
+
+func TestText(t *testing.T) {
+	test := func(v any, want string, wantBare bool) {
+		t.Helper()
+		s, bare, err := Text(reflect.ValueOf(v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != want || bare != wantBare {
+			t.Fatalf("%#v: have %q (%v), want %q (%v)", v, s, bare, want, wantBare)
+		}
+	}
+
+	test(true, "t", true)
+	test(int8(-1), "-1", true)
+	test(uint64(2), "2", true)
+	test(1.5, "1.5", true)
+	test(math.Inf(1), "Infinity", true)
+	test("x", "x", false)
+	test([]byte{0xde, 0xad}, `\xdead`, false)
+	test(time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC), "2024-01-02 03:04:05.000006+00:00", false)
+	test(time.Second, "1s", false)
+
+	if _, _, err := Text(reflect.ValueOf(struct{}{})); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("have %v, want %v", err, ErrUnsupported)
+	}
+}
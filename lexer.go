@@ -0,0 +1,163 @@
+package builq
+
+import "strings"
+
+type tokenKind byte
+
+const (
+	tokenText         tokenKind = iota // anything outside literals and comments.
+	tokenString                        // 'single quoted string'.
+	tokenIdent                         // "double quoted" or `backticked` identifier.
+	tokenDollarQuoted                  // $tag$ dollar quoted body $tag$.
+	tokenLineComment                   // -- line comment.
+	tokenBlockComment                  // /* block comment */.
+)
+
+// literalAt returns the kind and the end of a literal or a comment which starts at s[i].
+// For tokenText end is equal to i.
+func literalAt(s string, i int) (kind tokenKind, end int, err error) {
+	switch c := s[i]; {
+	case c == '\'':
+		end, err = quotedEnd(s, i, '\'')
+		return tokenString, end, err
+
+	case c == '"' || c == '`':
+		end, err = quotedEnd(s, i, c)
+		return tokenIdent, end, err
+
+	case c == '-' && strings.HasPrefix(s[i:], "--"):
+		end := strings.IndexByte(s[i:], '\n')
+		if end == -1 {
+			return tokenLineComment, len(s), nil
+		}
+		return tokenLineComment, i + end, nil
+
+	case c == '/' && strings.HasPrefix(s[i:], "/*"):
+		depth := 0
+		for j := i; j < len(s)-1; j++ {
+			switch s[j : j+2] {
+			case "/*":
+				depth++
+				j++
+			case "*/":
+				depth--
+				j++
+				if depth == 0 {
+					return tokenBlockComment, j + 1, nil
+				}
+			}
+		}
+		return tokenBlockComment, len(s), errUnterminatedLiteral
+
+	case c == '$':
+		tag, ok := dollarTag(s, i)
+		if !ok || (i > 0 && isIdentChar(s[i-1])) {
+			return tokenText, i, nil
+		}
+		body := i + len(tag)
+		end := strings.Index(s[body:], tag)
+		if end == -1 {
+			return tokenDollarQuoted, len(s), errUnterminatedLiteral
+		}
+		return tokenDollarQuoted, body + end + len(tag), nil
+
+	default:
+		return tokenText, i, nil
+	}
+}
+
+// normalizeFormat collapses whitespace outside literals into a single space
+// and converts line comments into block comments, so s can be written in one line.
+// On unterminated literal the rest of s is written as is.
+func normalizeFormat(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+
+	var space bool
+	for i := 0; i < len(s); {
+		if isSpace(s[i]) {
+			space = true
+			i++
+			continue
+		}
+		if space && sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		space = false
+
+		kind, end, err := literalAt(s, i)
+		switch {
+		case err != nil:
+			sb.WriteString(s[i:])
+			return sb.String()
+		case kind == tokenText:
+			sb.WriteByte(s[i])
+			i++
+		case kind == tokenLineComment:
+			text := strings.TrimSpace(s[i+2 : end])
+			text = strings.ReplaceAll(text, "*/", "* /")
+			text = strings.ReplaceAll(text, "/*", "/ *")
+			sb.WriteString("/* ")
+			if text != "" {
+				sb.WriteString(text)
+				sb.WriteByte(' ')
+			}
+			sb.WriteString("*/")
+			i = end
+		default:
+			sb.WriteString(s[i:end])
+			i = end
+		}
+	}
+	return sb.String()
+}
+
+// quotedEnd returns the end of a literal quoted with q, doubled q is an escaped quote.
+func quotedEnd(s string, i int, q byte) (int, error) {
+	for j := i + 1; j < len(s); j++ {
+		if s[j] != q {
+			continue
+		}
+		if j+1 < len(s) && s[j+1] == q {
+			j++
+			continue
+		}
+		return j + 1, nil
+	}
+	return len(s), errUnterminatedLiteral
+}
+
+// dollarTag returns a dollar quote tag like `$$` or `$body$` which starts at s[i].
+func dollarTag(s string, i int) (string, bool) {
+	j := i + 1
+	if j < len(s) && (s[j] == '_' || isLetter(s[j])) {
+		for j < len(s) && isIdentChar(s[j]) {
+			j++
+		}
+	}
+	if j >= len(s) || s[j] != '$' {
+		return "", false
+	}
+	return s[i : j+1], true
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\f', '\v':
+		return true
+	default:
+		return false
+	}
+}
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || isLetter(c) || isDigit(c) || c >= 0x80
+}
@@ -0,0 +1,97 @@
+package builq
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Padding returns the length a slice of n elements is padded to. See [Builder.SlicePadding].
+type Padding func(n int) int
+
+// PadPowersOfTwo pads a slice to the next power of two: 3 to 4, 5 to 8, 9 to 16 and so on.
+func PadPowersOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// PadBuckets pads a slice to the smallest bucket that fits it.
+// A slice larger than the largest bucket is padded to a multiple of it.
+// Buckets can be in any order, PadBuckets panics if a bucket isn't positive.
+func PadBuckets(buckets ...int) Padding {
+	buckets = append([]int(nil), buckets...)
+	sort.Ints(buckets)
+	if len(buckets) > 0 && buckets[0] <= 0 {
+		panic(fmt.Sprintf("builq: PadBuckets: bucket must be positive, have %d", buckets[0]))
+	}
+
+	return func(n int) int {
+		if len(buckets) == 0 {
+			return n
+		}
+		for _, bucket := range buckets {
+			if n <= bucket {
+				return bucket
+			}
+		}
+		last := buckets[len(buckets)-1]
+		return (n + last - 1) / last * last
+	}
+}
+
+// SlicePadding enables padding of `IN (...)` lists marked with [InList] by repeating the last element.
+// The query is semantically the same for `IN (...)` but the number of distinct
+// query shapes is capped, which is good for prepared statement caches.
+//
+// Padding changes the meaning of other lists, like `VALUES (%+$)`, so plain slices
+// and batches (`%#`) are never padded.
+func (b *Builder) SlicePadding(pad Padding) *Builder {
+	b.padding = pad
+	return b
+}
+
+// InListArg is a slice for `%+` verb inside `IN (...)`. See [InList].
+type InListArg struct {
+	Slice any
+}
+
+// InList marks a slice for `%+` verb as an `IN (...)` list, only such lists are padded.
+// See [Builder.SlicePadding].
+func InList(slice any) InListArg {
+	return InListArg{Slice: slice}
+}
+
+// Stats of the query.
+type Stats struct {
+	Placeholders int // placeholders written to the query.
+	Args         int // arguments returned.
+	Padded       int // placeholders added by [Builder.SlicePadding].
+}
+
+// Stats returns stats of the last [Builder.Build] call, useful for metrics.
+func (b *Builder) Stats() Stats {
+	return b.stats
+}
+
+// pad args with the last element according to the padding.
+func (b *Builder) pad(args []any) []any {
+	if b.padding == nil || len(args) == 0 {
+		return args
+	}
+	n := b.padding(len(args))
+	if n <= len(args) {
+		return args
+	}
+
+	if !b.debug {
+		b.stats.Padded += n - len(args)
+	}
+	padded := make([]any, n)
+	copy(padded, args)
+	for i := len(args); i < n; i++ {
+		padded[i] = args[len(args)-1]
+	}
+	return padded
+}
@@ -0,0 +1,75 @@
+package builq
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSlicePadding(t *testing.T) {
+	var b Builder
+	b.SlicePadding(PadPowersOfTwo)
+	b.Addf("SELECT * FROM users WHERE id IN (%+?) AND org = %?", InList([]int{1, 2, 3}), 42)
+	b.Addf("INSERT INTO t VALUES %#?", [][]any{{1, 2, 3}})
+	b.Addf("INSERT INTO t VALUES (%+?)", []int{1, 2, 3})
+
+	query, args, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT * FROM users WHERE id IN (?, ?, ?, ?) AND org = ?\nINSERT INTO t VALUES (?, ?, ?)\nINSERT INTO t VALUES (?, ?, ?)"
+	if query != wantQuery {
+		t.Fatalf("\nhave: %s\nwant: %s", query, wantQuery)
+	}
+	wantArgs := []any{1, 2, 3, 3, 42, 1, 2, 3, 1, 2, 3}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("\nhave: %v\nwant: %v", args, wantArgs)
+	}
+
+	wantStats := Stats{Placeholders: 11, Args: 11, Padded: 1}
+	if stats := b.Stats(); stats != wantStats {
+		t.Fatalf("\nhave: %+v\nwant: %+v", stats, wantStats)
+	}
+
+	wantDebug := "SELECT * FROM users WHERE id IN (1, 2, 3, 3) AND org = 42\nINSERT INTO t VALUES (1, 2, 3)\nINSERT INTO t VALUES (1, 2, 3)"
+	if debug := b.DebugBuild(); debug != wantDebug {
+		t.Fatalf("\nhave: %s\nwant: %s", debug, wantDebug)
+	}
+	if stats := b.Stats(); stats != wantStats {
+		t.Fatalf("debug must not change stats: %+v", stats)
+	}
+}
+
+func TestPadding(t *testing.T) {
+	test := func(pad Padding, n, want int) {
+		t.Helper()
+		if have := pad(n); have != want {
+			t.Errorf("pad(%d): have %d, want %d", n, have, want)
+		}
+	}
+
+	test(PadPowersOfTwo, 0, 1)
+	test(PadPowersOfTwo, 1, 1)
+	test(PadPowersOfTwo, 3, 4)
+	test(PadPowersOfTwo, 16, 16)
+	test(PadPowersOfTwo, 17, 32)
+
+	buckets := PadBuckets(10, 50, 100)
+	test(buckets, 1, 10)
+	test(buckets, 10, 10)
+	test(buckets, 11, 50)
+	test(buckets, 100, 100)
+	test(buckets, 101, 200)
+	test(PadBuckets(), 7, 7)
+	test(PadBuckets(100, 10, 50), 11, 50)
+
+	for _, bad := range [][]int{{0}, {10, -1}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("PadBuckets(%v) must panic", bad)
+				}
+			}()
+			PadBuckets(bad...)
+		}()
+	}
+}
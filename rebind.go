@@ -0,0 +1,187 @@
+package builq
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Style of the query placeholders.
+type Style byte
+
+// Supported placeholder styles.
+const (
+	StyleDollar   Style = '$' // PostgreSQL: $1, $2, $3.
+	StyleQuestion Style = '?' // MySQL/SQLite: ?, ?, ?.
+	StyleAt       Style = '@' // MSSQL: @p1, @p2, @p3.
+	StyleColon    Style = ':' // Oracle: :1, :2, :3.
+)
+
+// String implements the [fmt.Stringer] interface.
+func (s Style) String() string {
+	switch s {
+	case StyleDollar:
+		return "$n"
+	case StyleQuestion:
+		return "?"
+	case StyleAt:
+		return "@pN"
+	case StyleColon:
+		return ":n"
+	default:
+		return "Style(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+func (s Style) isValid() bool {
+	switch s {
+	case StyleDollar, StyleQuestion, StyleAt, StyleColon:
+		return true
+	default:
+		return false
+	}
+}
+
+// Rebind converts placeholders in a raw query from one style to another.
+//
+// Placeholders inside string literals, quoted identifiers, dollar-quoted bodies
+// and comments are ignored. An error is returned on ambiguous input: when query
+// already has placeholders of the target style, when `??`, `?|` or `?&` are found
+// (these are PostgreSQL operators) or when numbered placeholders are reused or
+// aren't in order but must be converted to `?`.
+func Rebind(query string, from, to Style) (string, error) {
+	if !from.isValid() || !to.isValid() {
+		return "", fmt.Errorf("%w: from %v to %v", errUnsupportedStyle, from, to)
+	}
+	if from == to {
+		return query, nil
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(query) + 10)
+
+	var counter int
+	for i := 0; i < len(query); {
+		kind, end, err := literalAt(query, i)
+		if err != nil {
+			return "", err
+		}
+		if kind != tokenText {
+			sb.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		if from == StyleQuestion && query[i] == '?' && isQuestionOperator(query, i) {
+			return "", fmt.Errorf("%w: %q operator", errAmbiguousPlaceholder, query[i:i+2])
+		}
+		if _, size, ok := placeholderAt(query, i, to); ok {
+			return "", fmt.Errorf("%w: query already has %q placeholder", errAmbiguousPlaceholder, query[i:i+size])
+		}
+
+		n, size, ok := placeholderAt(query, i, from)
+		if !ok {
+			sb.WriteByte(query[i])
+			i++
+			continue
+		}
+
+		counter++
+		switch {
+		case from == StyleQuestion:
+			n = counter
+		case to == StyleQuestion && n != counter:
+			return "", fmt.Errorf("%w: %q cannot be converted to '?' in place of %d", errAmbiguousPlaceholder, query[i:i+size], counter)
+		}
+
+		writePlaceholder(&sb, byte(to), n)
+		i += size
+	}
+	return sb.String(), nil
+}
+
+// SplitPlaceholders splits a raw query by placeholders of the style,
+// a query with n placeholders gives n+1 parts. Numbers of placeholders are dropped.
+//
+// Placeholders inside literals and comments are ignored, as in [Rebind].
+// For [StyleQuestion] `??`, `?|` and `?&` return an error, these are PostgreSQL operators.
+func SplitPlaceholders(query string, style Style) ([]string, error) {
+	if !style.isValid() {
+		return nil, fmt.Errorf("%w: %v", errUnsupportedStyle, style)
+	}
+
+	var parts []string
+	var last int
+	for i := 0; i < len(query); {
+		kind, end, err := literalAt(query, i)
+		if err != nil {
+			return nil, err
+		}
+		if kind != tokenText {
+			i = end
+			continue
+		}
+
+		if style == StyleQuestion && query[i] == '?' && isQuestionOperator(query, i) {
+			return nil, fmt.Errorf("%w: %q operator", errAmbiguousPlaceholder, query[i:i+2])
+		}
+		_, size, ok := placeholderAt(query, i, style)
+		if !ok {
+			i++
+			continue
+		}
+		parts = append(parts, query[last:i])
+		i += size
+		last = i
+	}
+	return append(parts, query[last:]), nil
+}
+
+// placeholderAt returns the number and the size of a placeholder which starts at s[i].
+// Number is always 0 for [StyleQuestion].
+func placeholderAt(s string, i int, style Style) (n, size int, ok bool) {
+	switch {
+	case s[i] != byte(style):
+		return 0, 0, false
+	case style == StyleQuestion:
+		return 0, 1, true
+	case i > 0 && (isIdentChar(s[i-1]) || s[i-1] == byte(style)):
+		return 0, 0, false
+	}
+
+	j := i + 1
+	if style == StyleAt {
+		if j >= len(s) || s[j] != 'p' {
+			return 0, 0, false
+		}
+		j++
+	}
+
+	start := j
+	for j < len(s) && isDigit(s[j]) {
+		j++
+	}
+	if j == start || (j < len(s) && isIdentChar(s[j])) {
+		return 0, 0, false
+	}
+	n, err := strconv.Atoi(s[start:j])
+	if err != nil {
+		return 0, 0, false
+	}
+	return n, j - i, true
+}
+
+// isQuestionOperator reports whether `?` at s[i] is a part of PostgreSQL `??`, `?|` or `?&` operators.
+func isQuestionOperator(s string, i int) bool {
+	if i+1 >= len(s) {
+		return false
+	}
+	switch s[i+1] {
+	case '?', '&':
+		return true
+	case '|':
+		return i+2 >= len(s) || s[i+2] != '|'
+	default:
+		return false
+	}
+}
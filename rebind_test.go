@@ -0,0 +1,92 @@
+package builq
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRebind(t *testing.T) {
+	test := func(query string, from, to Style, want string) {
+		t.Helper()
+		have, err := Rebind(query, from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have != want {
+			t.Errorf("\nhave: %s\nwant: %s", have, want)
+		}
+	}
+
+	test("SELECT * FROM t WHERE a = ? AND b IN (?, ?)", StyleQuestion, StyleDollar,
+		"SELECT * FROM t WHERE a = $1 AND b IN ($2, $3)")
+	test("SELECT * FROM t WHERE a = ? AND b IN (?, ?)", StyleQuestion, StyleAt,
+		"SELECT * FROM t WHERE a = @p1 AND b IN (@p2, @p3)")
+	test("SELECT * FROM t WHERE a = ? AND b IN (?, ?)", StyleQuestion, StyleColon,
+		"SELECT * FROM t WHERE a = :1 AND b IN (:2, :3)")
+	test("SELECT * FROM t WHERE a = $1 AND b = $2", StyleDollar, StyleQuestion,
+		"SELECT * FROM t WHERE a = ? AND b = ?")
+	test("SELECT * FROM t WHERE a = $2 AND b = $1 OR c = $2", StyleDollar, StyleAt,
+		"SELECT * FROM t WHERE a = @p2 AND b = @p1 OR c = @p2")
+	test("SELECT * FROM t WHERE a = @p1 AND b = @@ROWCOUNT", StyleAt, StyleDollar,
+		"SELECT * FROM t WHERE a = $1 AND b = @@ROWCOUNT")
+	test("SELECT a::int, arr[1:2] FROM t WHERE a = :1", StyleColon, StyleDollar,
+		"SELECT a::int, arr[1:2] FROM t WHERE a = $1")
+	test("SELECT a$1, $1 FROM t", StyleDollar, StyleQuestion,
+		"SELECT a$1, ? FROM t")
+	test("SELECT ?", StyleQuestion, StyleQuestion, "SELECT ?")
+
+	test("SELECT '?', 'it''s ?', \"?\", `?`, ? -- why?\nFROM t /* ? /* ? */ ? */ WHERE a = ?", StyleQuestion, StyleDollar,
+		"SELECT '?', 'it''s ?', \"?\", `?`, $1 -- why?\nFROM t /* ? /* ? */ ? */ WHERE a = $2")
+	test("CREATE FUNCTION f() AS $$ SELECT $1 $$; SELECT $body$ $1 $body$, $1", StyleDollar, StyleAt,
+		"CREATE FUNCTION f() AS $$ SELECT $1 $$; SELECT $body$ $1 $body$, @p1")
+	test("SELECT data ?| array['a'] FROM t WHERE a = $1", StyleDollar, StyleAt,
+		"SELECT data ?| array['a'] FROM t WHERE a = @p1")
+	test("SELECT a || ?||'x'", StyleQuestion, StyleDollar, "SELECT a || $1||'x'")
+}
+
+func TestRebindErrors(t *testing.T) {
+	test := func(query string, from, to Style, wantErr error) {
+		t.Helper()
+		_, err := Rebind(query, from, to)
+		if !errors.Is(err, wantErr) {
+			t.Errorf("\nhave: %v\nwant: %v", err, wantErr)
+		}
+	}
+
+	test("SELECT ?", Style('x'), StyleDollar, errUnsupportedStyle)
+	test("SELECT ?", StyleQuestion, Style('x'), errUnsupportedStyle)
+	test("SELECT 'abc", StyleQuestion, StyleDollar, errUnterminatedLiteral)
+	test("SELECT \"abc", StyleQuestion, StyleDollar, errUnterminatedLiteral)
+	test("SELECT /* abc", StyleQuestion, StyleDollar, errUnterminatedLiteral)
+	test("SELECT $$ abc", StyleDollar, StyleQuestion, errUnterminatedLiteral)
+	test("SELECT ? AND $1", StyleQuestion, StyleDollar, errAmbiguousPlaceholder)
+	test("SELECT data ?? 'a' AND ?", StyleQuestion, StyleDollar, errAmbiguousPlaceholder)
+	test("SELECT data ?& 'a' AND ?", StyleQuestion, StyleDollar, errAmbiguousPlaceholder)
+	test("SELECT data ? 'a' AND $1", StyleDollar, StyleQuestion, errAmbiguousPlaceholder)
+	test("SELECT $2, $1", StyleDollar, StyleQuestion, errAmbiguousPlaceholder)
+	test("SELECT $1, $1", StyleDollar, StyleQuestion, errAmbiguousPlaceholder)
+}
+
+func TestSplitPlaceholders(t *testing.T) {
+	parts, err := SplitPlaceholders(`SELECT "a?" FROM t WHERE b = ? AND c = '?' -- ?`, StyleQuestion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`SELECT "a?" FROM t WHERE b = `, ` AND c = '?' -- ?`}
+	if strings.Join(parts, "|") != strings.Join(want, "|") {
+		t.Fatalf("\nhave: %q\nwant: %q", parts, want)
+	}
+
+	parts, err = SplitPlaceholders("a = $1 AND b = $$x$$ AND c = $2", StyleDollar)
+	if err != nil || len(parts) != 3 {
+		t.Fatalf("have %q, %v", parts, err)
+	}
+
+	if _, err := SplitPlaceholders("data ?? 'key'", StyleQuestion); !errors.Is(err, errAmbiguousPlaceholder) {
+		t.Fatalf("have %v, want %v", err, errAmbiguousPlaceholder)
+	}
+	if _, err := SplitPlaceholders("a = ?", Style('x')); !errors.Is(err, errUnsupportedStyle) {
+		t.Fatalf("have %v, want %v", err, errUnsupportedStyle)
+	}
+}
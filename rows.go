@@ -0,0 +1,19 @@
+package builq
+
+// RowSource is a stream of rows for `%#` verb, an alternative to a slice of rows.
+// It's used with [Builder.Chunks] to build batch queries without loading all rows in memory.
+//
+// A source can be read only once: [Builder.Build] returns an error for it
+// and [Builder.Chunks] returns an error when it's called again.
+// If a source has `Stop()` method, [Chunks] calls it when done.
+type RowSource interface {
+	Next() (row []any, ok bool)
+}
+
+// RowsFunc is an adapter to use a function as [RowSource].
+type RowsFunc func() (row []any, ok bool)
+
+// Next implements the [RowSource] interface.
+func (f RowsFunc) Next() ([]any, bool) {
+	return f()
+}
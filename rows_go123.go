@@ -0,0 +1,29 @@
+//go:build go1.23
+
+package builq
+
+import "iter"
+
+// RowsSeq returns [RowSource] for an iterator.
+func RowsSeq(seq iter.Seq[[]any]) RowSource {
+	next, stop := iter.Pull(seq)
+	return &seqRows{next: next, stop: stop}
+}
+
+type seqRows struct {
+	next func() ([]any, bool)
+	stop func()
+}
+
+func (s *seqRows) Next() ([]any, bool) {
+	row, ok := s.next()
+	if !ok {
+		s.stop()
+	}
+	return row, ok
+}
+
+// Stop the iterator, it's safe to call it many times.
+func (s *seqRows) Stop() {
+	s.stop()
+}
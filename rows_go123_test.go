@@ -0,0 +1,88 @@
+//go:build go1.23
+
+package builq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRowsSeq(t *testing.T) {
+	var yielded int
+	stopped := false
+	seq := func(yield func([]any) bool) {
+		defer func() { stopped = true }()
+		for i := 0; i < 100; i++ {
+			yielded++
+			if !yield([]any{i, i * 2, i * 3}) {
+				return
+			}
+		}
+	}
+
+	var b Builder
+	b.Addf("INSERT INTO t (a, b, c) VALUES %#?", RowsSeq(seq))
+
+	chunks := b.Chunks(2)
+	if chunks.Next() {
+		t.Fatal("row with 3 values doesn't fit 2 placeholders")
+	}
+	if err := chunks.Err(); !errors.Is(err, errChunkLimit) {
+		t.Fatalf("have %v, want %v", err, errChunkLimit)
+	}
+	if !stopped {
+		t.Fatal("iterator must be stopped on error")
+	}
+
+	var q Builder
+	q.Addf("INSERT INTO t (a, b, c) VALUES %#?", RowsSeq(seq))
+
+	yielded, stopped = 0, false
+	var total int
+	chunks = q.Chunks(LimitSQLiteLegacy)
+	for chunks.Next() {
+		_, args := chunks.Query()
+		total += len(args)
+	}
+	if err := chunks.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if total != 300 || yielded != 100 || !stopped {
+		t.Fatalf("have %d args, %d yielded, stopped %v", total, yielded, stopped)
+	}
+}
+
+func TestRowsSeqClose(t *testing.T) {
+	stopped := false
+	seq := func(yield func([]any) bool) {
+		defer func() { stopped = true }()
+		for i := 0; ; i++ {
+			if !yield([]any{i}) {
+				return
+			}
+		}
+	}
+
+	var b Builder
+	b.Addf("INSERT INTO t (a) VALUES %#$", RowsSeq(seq))
+
+	chunks := b.Chunks(10)
+	for chunks.Next() {
+		break // like on an Exec error.
+	}
+	if stopped {
+		t.Fatal("iterator is stopped too early")
+	}
+	if err := chunks.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !stopped {
+		t.Fatal("iterator must be stopped on Close")
+	}
+	if chunks.Next() {
+		t.Fatal("no chunks after Close")
+	}
+	if err := chunks.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
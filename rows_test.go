@@ -0,0 +1,76 @@
+package builq
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// countingRows generates n rows and tracks how many were read.
+type countingRows struct {
+	n, read int
+}
+
+func (r *countingRows) Next() ([]any, bool) {
+	if r.read == r.n {
+		return nil, false
+	}
+	r.read++
+	return []any{r.read, "name"}, true
+}
+
+func TestRowSourceBuild(t *testing.T) {
+	src := &countingRows{n: 3}
+
+	var b Builder
+	b.Addf("INSERT INTO users (id, name) VALUES %#$", src)
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := b.Build(); !errors.Is(err, errRowSource) {
+			t.Fatalf("have %v, want %v", err, errRowSource)
+		}
+	}
+	if debug := b.DebugBuild(); debug != "INSERT INTO users (id, name) VALUES /* row source */" {
+		t.Fatalf("have %s", debug)
+	}
+	if src.read != 0 {
+		t.Fatalf("have %d rows read, want 0", src.read)
+	}
+}
+
+func TestRowSourceChunks(t *testing.T) {
+	src := &countingRows{n: 7}
+
+	var b Builder
+	b.Addf("INSERT INTO users (id, name) VALUES %#$ ON CONFLICT DO NOTHING", src)
+
+	var sizes []int
+	chunks := b.Chunks(6)
+	for chunks.Next() {
+		_, args := chunks.Query()
+		sizes = append(sizes, len(args))
+
+		// only the current chunk and a row of the next one are read.
+		if read, want := src.read, len(sizes)*3+1; read > want {
+			t.Fatalf("have %d rows read, want at most %d", read, want)
+		}
+	}
+	if err := chunks.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{6, 6, 2}; !reflect.DeepEqual(sizes, want) {
+		t.Fatalf("\nhave: %v\nwant: %v", sizes, want)
+	}
+
+	chunks = b.Chunks(6)
+	if chunks.Next() || !errors.Is(chunks.Err(), errRowSourceRead) {
+		t.Fatalf("have %v, want %v", chunks.Err(), errRowSourceRead)
+	}
+
+	var empty Builder
+	empty.Addf("INSERT INTO users (id, name) VALUES %#$", RowsFunc(func() ([]any, bool) { return nil, false }))
+	chunks = empty.Chunks(LimitPostgres)
+	if chunks.Next() || chunks.Err() != nil {
+		t.Fatalf("want no chunks, have %v", chunks.Err())
+	}
+}
@@ -0,0 +1,109 @@
+package builq
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Tag adds a key/value tag to the query, like route, controller or trace ID.
+// Tags are appended on build as a sqlcommenter comment: /*key='value',...*/
+// See https://google.github.io/sqlcommenter/spec/ for details.
+func (b *Builder) Tag(key, value string) *Builder {
+	if b.tags == nil {
+		b.tags = make(map[string]string)
+	}
+	b.tags[key] = value
+	return b
+}
+
+// TagPlacement defines where the tags comment is placed in the query.
+type TagPlacement byte
+
+const (
+	// TagsAppend places the comment at the end of the query but before a trailing semicolon,
+	// as the sqlcommenter spec requires. This is the default and fits PostgreSQL, SQLite and MSSQL.
+	TagsAppend TagPlacement = iota
+
+	// TagsPrepend places the comment before the query. It keeps the tags when a long query text
+	// is truncated: PostgreSQL `pg_stat_activity` (see `track_activity_query_size`) and
+	// MySQL `performance_schema` (see `performance_schema_max_sql_text_length`) do so at 1024 bytes.
+	TagsPrepend
+)
+
+// TagsPlacement sets where the tags comment goes. See [TagPlacement].
+func (b *Builder) TagsPlacement(placement TagPlacement) *Builder {
+	b.tagPlacement = placement
+	return b
+}
+
+// appendTags adds the tags comment to q with the position of [Builder.TagsPlacement].
+// When the query ends with a line comment, the appended tags comment goes to a new line,
+// a one-line query cannot have it, so an error is returned.
+//
+// Keys and values are URL-encoded, so the comment never starts with `/*!` or `/*+`
+// which are special in MySQL and Oracle.
+func (b *Builder) appendTags(q string) (string, error) {
+	keys := make([]string, 0, len(b.tags))
+	for k := range b.tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var comment strings.Builder
+	comment.WriteString("/*")
+	for i, k := range keys {
+		if i > 0 {
+			comment.WriteByte(',')
+		}
+		comment.WriteString(tagEscape(k))
+		comment.WriteString("='")
+		comment.WriteString(tagEscape(b.tags[k]))
+		comment.WriteByte('\'')
+	}
+	comment.WriteString("*/")
+
+	if b.tagPlacement == TagsPrepend {
+		return comment.String() + " " + q, nil
+	}
+
+	var semicolon string
+	if trimmed := strings.TrimRight(q, " \t\n;"); len(trimmed) < len(q) && strings.Contains(q[len(trimmed):], ";") {
+		q, semicolon = trimmed, ";"
+	}
+
+	sep := " "
+	if endsWithLineComment(q) {
+		if b.sep == ' ' {
+			return "", fmt.Errorf("%w, use TagsPrepend", errTagsAfterLineComment)
+		}
+		sep = "\n"
+	}
+	return q + sep + comment.String() + semicolon, nil
+}
+
+// tagEscape URL-encodes s with `%20` for spaces as sqlcommenter requires.
+// Quotes are encoded too (`%27`), so a value never closes its literal.
+func tagEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// endsWithLineComment reports whether q ends with a `--` comment.
+func endsWithLineComment(q string) bool {
+	var last tokenKind
+	for i := 0; i < len(q); i++ {
+		if isSpace(q[i]) {
+			continue
+		}
+		kind, end, err := literalAt(q, i)
+		if err != nil {
+			return kind == tokenLineComment
+		}
+		last = kind
+		if kind != tokenText {
+			i = end - 1
+		}
+	}
+	return last == tokenLineComment
+}
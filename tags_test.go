@@ -0,0 +1,54 @@
+package builq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTags(t *testing.T) {
+	test := func(b *Builder, want string) {
+		t.Helper()
+		have, _, err := b.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have != want {
+			t.Errorf("\nhave: %s\nwant: %s", have, want)
+		}
+	}
+
+	var b1 Builder
+	b1.Addf("SELECT * FROM users WHERE id = %$", 1)
+	b1.Tag("route", "/users/{id}").Tag("controller", "users").Tag("route", "^polls/$")
+	test(&b1, "SELECT * FROM users WHERE id = $1 /*controller='users',route='%5Epolls%2F%24'*/")
+
+	var b2 Builder
+	b2.Addf("SELECT 1;")
+	b2.Tag("name", "it's a query").Tag("db driver", "pgx")
+	test(&b2, "SELECT 1 /*db%20driver='pgx',name='it%27s%20a%20query'*/;")
+
+	var b3 Builder
+	b3.Addf("SELECT 1 -- legacy")
+	b3.Tag("trace", "abc")
+	test(&b3, "SELECT 1 -- legacy\n/*trace='abc'*/")
+
+	var b4 Builder
+	b4.Addf("SELECT '--' /* ; */")
+	b4.Tag("trace", "abc")
+	test(&b4, "SELECT '--' /* ; */ /*trace='abc'*/")
+
+	var b5 Builder
+	b5.Addf("SELECT 1;")
+	b5.Tag("trace", "abc").TagsPlacement(TagsPrepend)
+	test(&b5, "/*trace='abc'*/ SELECT 1;")
+
+	// a line comment from an argument cannot be followed in one line.
+	q := NewOneline()
+	q("SELECT a %s", "-- x")
+	q("FROM t").Tag("route", "r")
+	if _, _, err := q.Build(); !errors.Is(err, errTagsAfterLineComment) {
+		t.Fatalf("have %v, want %v", err, errTagsAfterLineComment)
+	}
+	q("").TagsPlacement(TagsPrepend)
+	test(q(""), "/*route='r'*/ SELECT a -- x FROM t")
+}
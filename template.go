@@ -0,0 +1,155 @@
+package builq
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// Template is a query format loaded at runtime and validated with the same verb grammar as [Builder.Addf].
+// See [LoadTemplates].
+type Template struct {
+	name   string
+	format string
+	verbs  []string
+}
+
+// Templates by name.
+type Templates map[string]*Template
+
+// Name of the template.
+func (t *Template) Name() string { return t.name }
+
+// String returns the template format.
+func (t *Template) String() string { return t.format }
+
+// Verbs of the template in order of appearance.
+func (t *Template) Verbs() []string { return t.verbs }
+
+// LoadTemplates loads query templates from files matched by patterns (see [fs.Glob]).
+//
+// A file is a single template named after the file without extension,
+// or it has a few templates, each starts with `-- name: Name` line.
+// Every template is validated, so unknown verbs or lonely `%` fail at startup.
+//
+// Security note: templates break the compile-time constant guarantee of [Builder.Addf].
+// This is fine for files embedded via [embed.FS], they are part of the binary and reviewed
+// as any other code, but never load templates from a user input or a writable location.
+// Consider code generation (see cmd/builq) to keep queries constant.
+func LoadTemplates(fsys fs.FS, patterns ...string) (Templates, error) {
+	templates := Templates{}
+	for _, pattern := range patterns {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("pattern %q matches no files", pattern)
+		}
+
+		for _, file := range files {
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return nil, err
+			}
+			if err := templates.parse(file, string(data)); err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+		}
+	}
+	return templates, nil
+}
+
+// Lookup returns a template by name or an error if there is no such template.
+func (ts Templates) Lookup(name string) (*Template, error) {
+	t, ok := ts[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errNoTemplate, name)
+	}
+	return t, nil
+}
+
+// MustLoadTemplates works as [LoadTemplates] but panics on error.
+func MustLoadTemplates(fsys fs.FS, patterns ...string) Templates {
+	templates, err := LoadTemplates(fsys, patterns...)
+	if err != nil {
+		panic(err)
+	}
+	return templates
+}
+
+func (ts Templates) parse(file, data string) error {
+	name := strings.TrimSuffix(path.Base(file), path.Ext(file))
+
+	var body strings.Builder
+	add := func() error {
+		format := strings.TrimSpace(body.String())
+		body.Reset()
+		if format == "" {
+			return nil
+		}
+		if _, ok := ts[name]; ok {
+			return fmt.Errorf("duplicate template %q", name)
+		}
+		verbs, err := Verbs(format, LiteralVerbs)
+		if err != nil {
+			return fmt.Errorf("template %q: %w", name, err)
+		}
+		ts[name] = &Template{name: name, format: format, verbs: verbs}
+		return nil
+	}
+
+	for _, line := range strings.SplitAfter(data, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "--") {
+			body.WriteString(line)
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimSpace(trimmed[2:]), ":")
+		if !ok || key != "name" {
+			body.WriteString(line)
+			continue
+		}
+
+		if err := add(); err != nil {
+			return err
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			return fmt.Errorf("template name is empty")
+		}
+		name = fields[0]
+	}
+	return add()
+}
+
+// AddTemplate adds a template with args, same as [Builder.Addf] for a constant format.
+// A nil template (like a missing key of [Templates]) makes [Builder.Build] return an error.
+func (b *Builder) AddTemplate(t *Template, args ...any) *Builder {
+	if b.sep == 0 {
+		b.sep = '\n'
+	}
+	return b.addTemplate(t, args...)
+}
+
+// AddTemplate adds a template with args, same as [OnelineBuilder.Addf] for a constant format.
+// A nil template (like a missing key of [Templates]) makes [Builder.Build] return an error.
+func (b *OnelineBuilder) AddTemplate(t *Template, args ...any) *Builder {
+	if b.sep == 0 {
+		b.sep = ' '
+	}
+	b.normalize = true
+	return b.addTemplate(t, args...)
+}
+
+func (b *Builder) addTemplate(t *Template, args ...any) *Builder {
+	if t == nil {
+		if b.err == nil {
+			b.err = errNilTemplate
+		}
+		return b
+	}
+	return b.addf(constString(t.format), args...)
+}
@@ -0,0 +1,81 @@
+package builq
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestLoadTemplates(t *testing.T) {
+	templates, err := LoadTemplates(os.DirFS("testdata"), "templates/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 3 {
+		t.Fatalf("have %d templates", len(templates))
+	}
+
+	get := templates["GetUser"]
+	if get.Name() != "GetUser" || len(get.Verbs()) != 2 {
+		t.Fatalf("have %q with %v verbs", get.Name(), get.Verbs())
+	}
+	if want := "-- returns a single user\nSELECT %s FROM users WHERE id = %$;"; get.String() != want {
+		t.Fatalf("\nhave: %s\nwant: %s", get.String(), want)
+	}
+
+	var b Builder
+	b.AddTemplate(get, Columns{"id", "name"}, 42)
+	b.AddTemplate(templates["count_orders"], 42)
+	query, args, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "-- returns a single user\nSELECT id, name FROM users WHERE id = $1;\nSELECT count(*) FROM orders WHERE user_id = $2;"
+	if query != want || len(args) != 2 {
+		t.Fatalf("\nhave: %s\nwant: %s", query, want)
+	}
+
+	var ob OnelineBuilder
+	ob.AddTemplate(templates["ListUsers"], []int{1, 2})
+	query, _, err = ob.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT * FROM users WHERE id IN ($1, $2) AND name LIKE 'a%';"; query != want {
+		t.Fatalf("\nhave: %s\nwant: %s", query, want)
+	}
+}
+
+func TestLoadTemplatesErrors(t *testing.T) {
+	fsys := os.DirFS("testdata")
+
+	if _, err := LoadTemplates(fsys, "badtemplates/*.sql"); !errors.Is(err, errUnsupportedVerb) {
+		t.Fatalf("have %v, want %v", err, errUnsupportedVerb)
+	}
+	if _, err := LoadTemplates(fsys, "templates/*.sql", "templates/users.sql"); err == nil {
+		t.Fatal("want duplicate error")
+	}
+	if _, err := LoadTemplates(fsys, "nothing/*.sql"); err == nil {
+		t.Fatal("want no files error")
+	}
+}
+
+func TestMissingTemplate(t *testing.T) {
+	tmpls := MustLoadTemplates(os.DirFS("testdata"), "templates/*.sql")
+
+	if _, err := tmpls.Lookup("typo"); !errors.Is(err, errNoTemplate) {
+		t.Fatalf("have %v, want %v", err, errNoTemplate)
+	}
+
+	var b Builder
+	b.AddTemplate(tmpls["typo"], 42)
+	if _, _, err := b.Build(); !errors.Is(err, errNilTemplate) {
+		t.Fatalf("have %v, want %v", err, errNilTemplate)
+	}
+
+	var ob OnelineBuilder
+	ob.AddTemplate(tmpls["typo"], 42)
+	if _, _, err := ob.Build(); !errors.Is(err, errNilTemplate) {
+		t.Fatalf("have %v, want %v", err, errNilTemplate)
+	}
+}
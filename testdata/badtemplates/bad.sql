@@ -0,0 +1,2 @@
+-- name: Bad
+SELECT * FROM users WHERE name LIKE 'a%';
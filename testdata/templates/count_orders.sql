@@ -0,0 +1 @@
+SELECT count(*) FROM orders WHERE user_id = %$;
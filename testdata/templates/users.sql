@@ -0,0 +1,6 @@
+-- name: GetUser
+-- returns a single user
+SELECT %s FROM users WHERE id = %$;
+
+-- name: ListUsers :many
+SELECT * FROM users WHERE id IN (%+$) AND name LIKE 'a%%';
@@ -0,0 +1,141 @@
+package builq
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TypedArg is an argument with an explicit ClickHouse type for `%{` verb.
+type TypedArg struct {
+	Value any
+	Type  string
+}
+
+// Typed returns v with an explicit ClickHouse type for `%{` verb, like "LowCardinality(String)".
+// v can be [sql.NamedArg] to set a parameter name.
+func Typed(v any, typ string) TypedArg {
+	return TypedArg{Value: v, Type: typ}
+}
+
+// writeTyped writes ClickHouse parameter `{pN:Type}`, argument is returned as [sql.NamedArg].
+func (b *Builder) writeTyped(sb *strings.Builder, resArgs *[]any, arg any) error {
+	var typ string
+	if typed, ok := arg.(TypedArg); ok {
+		if !isTypeName(typed.Type) {
+			return fmt.Errorf("%w: %q", errInvalidTypeName, typed.Type)
+		}
+		typ, arg = typed.Type, typed.Value
+	}
+
+	name := "p" + strconv.Itoa(b.counter)
+	if named, ok := arg.(sql.NamedArg); ok {
+		if !isArgName(named.Name) {
+			return fmt.Errorf("%w: %q", errInvalidArgName, named.Name)
+		}
+		name, arg = named.Name, named.Value
+	}
+
+	if typ == "" {
+		var err error
+		if typ, err = clickHouseType(reflect.TypeOf(arg)); err != nil {
+			return fmt.Errorf("%w %T, use Typed", err, arg)
+		}
+	}
+
+	sb.WriteByte('{')
+	sb.WriteString(name)
+	sb.WriteByte(':')
+	sb.WriteString(typ)
+	sb.WriteByte('}')
+	*resArgs = append(*resArgs, sql.Named(name, arg))
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func clickHouseType(t reflect.Type) (string, error) {
+	if t == nil {
+		return "", errUnknownType
+	}
+	if t == timeType {
+		return "DateTime64(6)", nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "Bool", nil
+	case reflect.String:
+		return "String", nil
+	case reflect.Int8:
+		return "Int8", nil
+	case reflect.Int16:
+		return "Int16", nil
+	case reflect.Int32:
+		return "Int32", nil
+	case reflect.Int, reflect.Int64:
+		return "Int64", nil
+	case reflect.Uint8:
+		return "UInt8", nil
+	case reflect.Uint16:
+		return "UInt16", nil
+	case reflect.Uint32:
+		return "UInt32", nil
+	case reflect.Uint, reflect.Uint64:
+		return "UInt64", nil
+	case reflect.Float32:
+		return "Float32", nil
+	case reflect.Float64:
+		return "Float64", nil
+
+	case reflect.Pointer:
+		elem, err := clickHouseType(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		return "Nullable(" + elem + ")", nil
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "String", nil
+		}
+		elem, err := clickHouseType(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		return "Array(" + elem + ")", nil
+
+	case reflect.Map:
+		key, err := clickHouseType(t.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := clickHouseType(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		return "Map(" + key + ", " + elem + ")", nil
+
+	default:
+		return "", errUnknownType
+	}
+}
+
+// isTypeName reports whether s is safe to be written as a type, like `{name:Type}` or `$1::type[]`.
+func isTypeName(s string) bool {
+	if s == "" || !isLetter(s[0]) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '_' || c == '(' || c == ')' || c == ',' || c == ' ' || c == '.':
+		case isLetter(c) || isDigit(c):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package builq
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTyped(t *testing.T) {
+	var b Builder
+	b.Addf("SELECT * FROM events WHERE user_id = %{ AND kind IN (%+{)", uint64(42), []string{"click", "view"})
+	b.Addf("AND ts > %{ AND tag = %{", time.Unix(0, 0), Typed(sql.Named("tag", "x"), "LowCardinality(String)"))
+	b.Addf("AND ids = %{ AND score = %{", []int32{1}, (*float64)(nil))
+
+	query, args, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT * FROM events WHERE user_id = {p1:UInt64} AND kind IN ({p2:String}, {p3:String})\n" +
+		"AND ts > {p4:DateTime64(6)} AND tag = {tag:LowCardinality(String)}\n" +
+		"AND ids = {p6:Array(Int32)} AND score = {p7:Nullable(Float64)}"
+	if query != wantQuery {
+		t.Fatalf("\nhave: %s\nwant: %s", query, wantQuery)
+	}
+	wantArgs := []any{
+		sql.Named("p1", uint64(42)), sql.Named("p2", "click"), sql.Named("p3", "view"),
+		sql.Named("p4", time.Unix(0, 0)), sql.Named("tag", "x"),
+		sql.Named("p6", []int32{1}), sql.Named("p7", (*float64)(nil)),
+	}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("\nhave: %v\nwant: %v", args, wantArgs)
+	}
+}
+
+func TestTypedBatch(t *testing.T) {
+	var b Builder
+	b.Addf("INSERT INTO t VALUES %#{", [][]any{{1, "a"}, {2, Typed("b", "FixedString(1)")}})
+
+	query, _, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "INSERT INTO t VALUES ({p1:Int64}, {p2:String}), ({p3:Int64}, {p4:FixedString(1)})"
+	if query != want {
+		t.Fatalf("\nhave: %s\nwant: %s", query, want)
+	}
+
+	want = "INSERT INTO t VALUES (1, 'a'), (2, 'b')"
+	if debug := b.DebugBuild(); debug != want {
+		t.Fatalf("\nhave: %s\nwant: %s", debug, want)
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	test := func(wantErr error, arg any) {
+		t.Helper()
+		var b Builder
+		b.Addf("WHERE a = %{", arg)
+		if _, _, err := b.Build(); !errors.Is(err, wantErr) {
+			t.Fatalf("have %v, want %v", err, wantErr)
+		}
+	}
+
+	test(errUnknownType, nil)
+	test(errUnknownType, struct{}{})
+	test(errInvalidTypeName, Typed(1, "UInt8} OR 1=1 --"))
+	test(errInvalidTypeName, Typed(1, ""))
+	test(errInvalidArgName, sql.Named("a}", 1))
+}
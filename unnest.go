@@ -0,0 +1,188 @@
+package builq
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cristalhq/builq/internal/values"
+)
+
+// UnnestArg is an argument for `%U$` verb with explicit column types. See [Unnest].
+type UnnestArg struct {
+	Rows  any
+	Types []string
+}
+
+// Unnest returns rows for `%U$` verb with explicit PostgreSQL column types, like "int" or "text".
+// Types are required for an empty rows and for columns which type cannot be inferred.
+func Unnest(rows any, types ...string) UnnestArg {
+	return UnnestArg{Rows: rows, Types: types}
+}
+
+// writeUnnest writes `unnest($1::bigint[], $2::text[])` with a single array argument per column.
+func (b *Builder) writeUnnest(sb *strings.Builder, resArgs *[]any, verb byte, arg any) error {
+	if verb != '$' {
+		return fmt.Errorf("%w: 'U' requires '$'", errUnsupportedVerb)
+	}
+
+	var types []string
+	if u, ok := arg.(UnnestArg); ok {
+		for _, typ := range u.Types {
+			if !isTypeName(typ) {
+				return fmt.Errorf("%w: %q", errInvalidTypeName, typ)
+			}
+		}
+		arg, types = u.Rows, u.Types
+	}
+
+	columns, err := b.transpose(arg, len(types))
+	if err != nil {
+		return err
+	}
+	if types == nil {
+		if types, err = inferColumnTypes(columns); err != nil {
+			return err
+		}
+	}
+	if len(types) != len(columns) {
+		return fmt.Errorf("%w: have %d types for %d columns", errMismatchedRows, len(types), len(columns))
+	}
+
+	enc := b.arrayEncoder
+	if enc == nil {
+		enc = encodeArray
+	}
+
+	sb.WriteString("unnest(")
+	for i, column := range columns {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+
+		if b.debug {
+			sb.WriteString("ARRAY[")
+			for j, v := range column {
+				if j > 0 {
+					sb.WriteString(", ")
+				}
+				b.writeDebug(sb, v)
+			}
+			sb.WriteByte(']')
+		} else {
+			value, err := enc(column)
+			if err != nil {
+				return err
+			}
+			if err := b.writeArg(sb, resArgs, verb, value); err != nil {
+				return err
+			}
+		}
+
+		sb.WriteString("::")
+		sb.WriteString(types[i])
+		sb.WriteString("[]")
+	}
+	sb.WriteByte(')')
+	return nil
+}
+
+// transpose rows (slices or structs) into columns.
+// For empty rows there are width empty columns.
+func (b *Builder) transpose(arg any, width int) ([][]any, error) {
+	rows, err := b.asSlice(arg)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		if width == 0 {
+			return nil, errEmptySlice
+		}
+		columns := make([][]any, width)
+		for i := range columns {
+			columns[i] = []any{}
+		}
+		return columns, nil
+	}
+
+	var columns [][]any
+	for i, row := range rows {
+		values, err := b.asRow(row)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			columns = make([][]any, len(values))
+			for j := range columns {
+				columns[j] = make([]any, 0, len(rows))
+			}
+		}
+		if len(values) != len(columns) {
+			return nil, fmt.Errorf("%w: row %d has %d columns, want %d", errMismatchedRows, i, len(values), len(columns))
+		}
+		for j, v := range values {
+			columns[j] = append(columns[j], v)
+		}
+	}
+	return columns, nil
+}
+
+func inferColumnTypes(columns [][]any) ([]string, error) {
+	types := make([]string, len(columns))
+	for i, column := range columns {
+		for _, v := range column {
+			typ, ok, err := postgresType(v)
+			if err != nil {
+				return nil, fmt.Errorf("column %d: %w", i+1, err)
+			}
+			if ok {
+				types[i] = typ
+				break
+			}
+		}
+		if types[i] == "" {
+			return nil, fmt.Errorf("%w of column %d, use Unnest", errUnknownType, i+1)
+		}
+	}
+	return types, nil
+}
+
+// postgresType returns a PostgreSQL type for v, ok is false for nil.
+func postgresType(v any) (typ string, ok bool, err error) {
+	value, isNull, err := values.Resolve(reflect.ValueOf(v))
+	switch {
+	case err != nil:
+		return "", false, err
+	case isNull && value.Kind() == reflect.Slice:
+		// nil []byte is NULL but still has a type.
+	case isNull:
+		return "", false, nil
+	}
+	if value.Type() == timeType {
+		return "timestamptz", true, nil
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		return "boolean", true, nil
+	case reflect.String:
+		return "text", true, nil
+	case reflect.Int8, reflect.Int16, reflect.Uint8:
+		return "smallint", true, nil
+	case reflect.Int32, reflect.Uint16:
+		return "integer", true, nil
+	case reflect.Int, reflect.Int64, reflect.Uint32:
+		return "bigint", true, nil
+	case reflect.Uint, reflect.Uint64:
+		return "numeric", true, nil
+	case reflect.Float32:
+		return "real", true, nil
+	case reflect.Float64:
+		return "double precision", true, nil
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return "bytea", true, nil
+		}
+	}
+	return "", false, fmt.Errorf("%w %s, use Unnest", errUnknownType, value.Type())
+}
@@ -0,0 +1,111 @@
+package builq
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUnnest(t *testing.T) {
+	rows := [][]any{{1, "a", true}, {2, "b", false}}
+
+	var b Builder
+	b.Addf("INSERT INTO users (id, name, active) SELECT * FROM %U$", rows)
+	b.Addf("RETURNING id, %$", "x")
+
+	query, args, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "INSERT INTO users (id, name, active) SELECT * FROM unnest($1::bigint[], $2::text[], $3::boolean[])\nRETURNING id, $4"
+	if query != wantQuery {
+		t.Fatalf("\nhave: %s\nwant: %s", query, wantQuery)
+	}
+	wantArgs := []any{arrayValue("{1,2}"), arrayValue(`{"a","b"}`), arrayValue("{t,f}"), "x"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("\nhave: %v\nwant: %v", args, wantArgs)
+	}
+
+	wantDebug := "INSERT INTO users (id, name, active) SELECT * FROM unnest(ARRAY[1, 2]::bigint[], ARRAY['a', 'b']::text[], ARRAY['true', 'false']::boolean[])\nRETURNING id, 'x'"
+	if debug := b.DebugBuild(); debug != wantDebug {
+		t.Fatalf("\nhave: %s\nwant: %s", debug, wantDebug)
+	}
+}
+
+func TestUnnestStructs(t *testing.T) {
+	type event struct {
+		ID    int32          `db:"id"`
+		At    *time.Time     `db:"at"`
+		Note  sql.NullString `db:"note"`
+		Data  []byte         `db:"data"`
+		Score float64        `db:"score"`
+	}
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := []event{
+		{ID: 1, Note: sql.NullString{}, Score: 0.5},
+		{ID: 2, At: &ts, Note: sql.NullString{String: "n", Valid: true}, Data: []byte{1}},
+	}
+
+	var b Builder
+	b.Addf("SELECT * FROM %U$", rows)
+
+	query, args, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT * FROM unnest($1::integer[], $2::timestamptz[], $3::text[], $4::bytea[], $5::double precision[])"
+	if query != want {
+		t.Fatalf("\nhave: %s\nwant: %s", query, want)
+	}
+	if len(args) != 5 {
+		t.Fatalf("have %d args", len(args))
+	}
+
+	var typed Builder
+	typed.ArrayEncoder(func(slice any) (any, error) { return slice, nil })
+	typed.Addf("SELECT * FROM %U$", Unnest(rows, "int", "timestamp", "varchar(10)", "bytea", "numeric"))
+
+	query, args, err = typed.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "SELECT * FROM unnest($1::int[], $2::timestamp[], $3::varchar(10)[], $4::bytea[], $5::numeric[])"
+	if query != want {
+		t.Fatalf("\nhave: %s\nwant: %s", query, want)
+	}
+	if !reflect.DeepEqual(args[0], []any{int32(1), int32(2)}) {
+		t.Fatalf("have %v", args[0])
+	}
+
+	var empty Builder
+	empty.Addf("SELECT * FROM %U$", Unnest([]event{}, "int", "text"))
+	query, _, err = empty.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT * FROM unnest($1::int[], $2::text[])"; query != want {
+		t.Fatalf("\nhave: %s\nwant: %s", query, want)
+	}
+}
+
+func TestUnnestErrors(t *testing.T) {
+	test := func(wantErr error, format string, arg any) {
+		t.Helper()
+		var b Builder
+		b.Addf(constString(format), arg)
+		if _, _, err := b.Build(); !errors.Is(err, wantErr) {
+			t.Fatalf("have %v, want %v", err, wantErr)
+		}
+	}
+
+	test(errUnsupportedVerb, "SELECT * FROM %U?", [][]any{{1}})
+	test(errNonSliceArgument, "SELECT * FROM %U$", 1)
+	test(errEmptySlice, "SELECT * FROM %U$", [][]any{})
+	test(errMismatchedRows, "SELECT * FROM %U$", [][]any{{1, 2}, {3}})
+	test(errMismatchedRows, "SELECT * FROM %U$", Unnest([][]any{{1, 2}}, "int"))
+	test(errUnknownType, "SELECT * FROM %U$", [][]any{{nil}, {nil}})
+	test(errUnknownType, "SELECT * FROM %U$", [][]any{{struct{}{}}})
+	test(errInvalidTypeName, "SELECT * FROM %U$", Unnest([][]any{{1}}, "int[]); DROP TABLE users; --"))
+}
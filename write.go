@@ -1,6 +1,7 @@
 package builq
 
 import (
+	"database/sql"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -10,7 +11,10 @@ import (
 
 func (b *Builder) write(sb *strings.Builder, resArgs *[]any, s string, args ...any) error {
 	for argID := 0; ; argID++ {
-		idx := strings.IndexByte(s, '%')
+		idx, err := b.indexVerb(s)
+		if err != nil {
+			return err
+		}
 		if idx == -1 {
 			var err error
 			if len(args) != argID {
@@ -25,67 +29,140 @@ func (b *Builder) write(sb *strings.Builder, resArgs *[]any, s string, args ...a
 		sb.WriteString(s[:idx])
 
 		s = s[idx+1:] // skip '%'
-		if len(s) == 0 {
-			return errLonelyModifier
+		mod, verb, size, err := parseVerb(s)
+		if err != nil {
+			return err
 		}
+		s = s[size:]
 
-		switch verb := s[0]; verb {
-		case '$', '?', '@', 's', 'd':
-			if argID >= len(args) {
-				return fmt.Errorf("%w: have %d args, want %d", errTooFewArguments, len(args), argID+1)
-			}
+		if verb == '%' {
+			argID--
+			sb.WriteByte('%')
+			continue
+		}
 
-			arg := args[argID]
-			s = s[1:]
-			if err := b.writeArg(sb, resArgs, verb, arg); err != nil {
-				return err
-			}
+		if argID >= len(args) {
+			return fmt.Errorf("%w: have %d args, want %d", errTooFewArguments, len(args), argID+1)
+		}
+		arg := args[argID]
 
-		case '+', '#':
-			isBatch := verb == '#'
-			s = s[1:]
-			if len(s) < 1 || s[0] == ' ' {
-				return fmt.Errorf("%w: '%c' requires additional '$', '?' or '@'", errIncorrectVerb, verb)
-			}
+		switch mod {
+		case 0:
+			err = b.writeArg(sb, resArgs, verb, arg)
+		case '#':
+			err = b.writeBatch(sb, resArgs, verb, arg)
+		case '+':
+			err = b.writeList(sb, resArgs, verb, arg)
+		case 'e':
+			err = b.writeExpr(sb, resArgs, verb, arg)
+		case 'A':
+			err = b.writeArray(sb, resArgs, verb, arg)
+		case 'U':
+			err = b.writeUnnest(sb, resArgs, verb, arg)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
 
-			switch verb := s[0]; verb {
-			case '$', '?', '@':
-				if argID >= len(args) {
-					return fmt.Errorf("%w: have %d args, want %d", errTooFewArguments, len(args), argID+1)
-				}
-
-				arg := args[argID]
-				s = s[1:]
-
-				if isBatch {
-					if err := b.writeBatch(sb, resArgs, verb, arg); err != nil {
-						return err
-					}
-				} else {
-					if err := b.writeSlice(sb, resArgs, verb, arg); err != nil {
-						return err
-					}
-				}
-
-			default:
-				return fmt.Errorf("%w: '%c' is not supported", errUnsupportedVerb, verb)
-			}
+// Verbs returns verbs of the format in order of appearance, like "%$" or "%+?".
+// Escaped `%%` isn't a verb, literals and comments are handled as the builder
+// with the same mode does, see [Builder.Literals]. Useful for tools that inspect queries.
+func Verbs(format string, mode LiteralMode) ([]string, error) {
+	var verbs []string
+	for {
+		idx, err := indexVerb(format, mode)
+		if err != nil {
+			return nil, err
+		}
+		if idx == -1 {
+			return verbs, nil
+		}
 
-		case '%':
-			argID--
-			s = s[1:]
-			sb.WriteByte('%')
+		format = format[idx+1:] // skip '%'
+		_, verb, size, err := parseVerb(format)
+		if err != nil {
+			return nil, err
+		}
+		if verb != '%' {
+			verbs = append(verbs, "%"+format[:size])
+		}
+		format = format[size:]
+	}
+}
+
+// parseVerb parses a verb in s which starts right after '%'.
+// Returns an optional modifier (like '+'), the verb itself and the number of parsed bytes.
+func parseVerb(s string) (mod, verb byte, size int, err error) {
+	if len(s) == 0 {
+		return 0, 0, 0, errLonelyModifier
+	}
+
+	switch verb := s[0]; verb {
+	case '$', '?', '@', ':', '{', 's', 'd', '%':
+		return 0, verb, 1, nil
 
-		case ' ':
-			return errLonelyModifier
+	case '+', '#', 'e', 'A', 'U':
+		if len(s) < 2 || s[1] == ' ' {
+			return 0, 0, 0, fmt.Errorf("%w: '%c' requires additional '$', '?', '@', ':' or '{'", errIncorrectVerb, verb)
+		}
 
+		switch s[1] {
+		case '$', '?', '@', ':', '{':
+			return verb, s[1], 2, nil
 		default:
-			return fmt.Errorf("%w: '%c' is not supported", errUnsupportedVerb, verb)
+			return 0, 0, 0, fmt.Errorf("%w: '%c' is not supported", errUnsupportedVerb, s[1])
+		}
+
+	case ' ':
+		return 0, 0, 0, errLonelyModifier
+
+	default:
+		return 0, 0, 0, fmt.Errorf("%w: '%c' is not supported", errUnsupportedVerb, verb)
+	}
+}
+
+func (b *Builder) indexVerb(s string) (int, error) {
+	return indexVerb(s, b.literals)
+}
+
+// indexVerb returns the index of the next '%' in s.
+// Depending on the literal mode, literals and comments are skipped or rejected.
+func indexVerb(s string, mode LiteralMode) (int, error) {
+	if mode == LiteralVerbs {
+		return strings.IndexByte(s, '%'), nil
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' {
+			return i, nil
 		}
+
+		kind, end, err := literalAt(s, i)
+		switch {
+		case err != nil:
+			return 0, fmt.Errorf("%w: %s", err, s[i:end])
+		case kind == tokenText:
+			continue
+		case mode == LiteralError && strings.IndexByte(s[i:end], '%') != -1:
+			return 0, fmt.Errorf("%w: %s", errVerbInLiteral, s[i:end])
+		}
+		i = end - 1
 	}
+	return -1, nil
 }
 
 func (b *Builder) writeBatch(sb *strings.Builder, resArgs *[]any, verb byte, arg any) error {
+	// a source is read once, so only chunks can read it, see [Builder.Chunks].
+	if _, ok := arg.(RowSource); ok {
+		if b.debug {
+			sb.WriteString("/* row source */")
+			return nil
+		}
+		return errRowSource
+	}
+
 	args, err := b.asSlice(arg)
 	if err != nil {
 		return err
@@ -95,7 +172,11 @@ func (b *Builder) writeBatch(sb *strings.Builder, resArgs *[]any, verb byte, arg
 			sb.WriteString(", ")
 		}
 		sb.WriteByte('(')
-		if err := b.writeSlice(sb, resArgs, verb, arg); err != nil {
+		row, err := b.asRow(arg)
+		if err != nil {
+			return err
+		}
+		if err := b.writeArgs(sb, resArgs, verb, row); err != nil {
 			return err
 		}
 		sb.WriteByte(')')
@@ -103,11 +184,26 @@ func (b *Builder) writeBatch(sb *strings.Builder, resArgs *[]any, verb byte, arg
 	return nil
 }
 
-func (b *Builder) writeSlice(sb *strings.Builder, resArgs *[]any, verb byte, arg any) error {
+func (b *Builder) writeList(sb *strings.Builder, resArgs *[]any, verb byte, arg any) error {
+	list, isInList := arg.(InListArg)
+	if isInList {
+		arg = list.Slice
+	}
+
 	args, err := b.asSlice(arg)
 	if err != nil {
 		return err
 	}
+	if len(args) == 0 {
+		return b.writeEmpty(sb, verb)
+	}
+	if isInList {
+		args = b.pad(args)
+	}
+	return b.writeArgs(sb, resArgs, verb, args)
+}
+
+func (b *Builder) writeArgs(sb *strings.Builder, resArgs *[]any, verb byte, args []any) error {
 	for i, arg := range args {
 		if i > 0 {
 			sb.WriteString(", ")
@@ -119,6 +215,40 @@ func (b *Builder) writeSlice(sb *strings.Builder, resArgs *[]any, verb byte, arg
 	return nil
 }
 
+// writeEmpty writes an empty list according to [EmptySliceMode].
+func (b *Builder) writeEmpty(sb *strings.Builder, verb byte) error {
+	switch b.emptySlices {
+	case EmptySliceNull:
+		sb.WriteString("NULL")
+	case EmptySliceFalse:
+		switch verb {
+		case '$':
+			sb.WriteString("SELECT NULL WHERE FALSE")
+		case ':':
+			sb.WriteString("SELECT NULL FROM DUAL WHERE 1=0")
+		default:
+			sb.WriteString("SELECT NULL WHERE 1=0")
+		}
+	default:
+		// debug query is printed as is, so keep it going and show the cause.
+		if b.debug {
+			sb.WriteString("/* empty slice */")
+			return nil
+		}
+		return errEmptySlice
+	}
+	return nil
+}
+
+func (b *Builder) writeExpr(sb *strings.Builder, resArgs *[]any, verb byte, arg any) error {
+	expr, ok := arg.(Expr)
+	if !ok {
+		return fmt.Errorf("%w: got %T", errNonExprArgument, arg)
+	}
+	w := &Writer{b: b, sb: sb, resArgs: resArgs, verb: verb}
+	return expr.WriteSQL(w)
+}
+
 func (b *Builder) writeArg(sb *strings.Builder, resArgs *[]any, verb byte, arg any) error {
 	if b.debug {
 		b.writeDebug(sb, arg)
@@ -130,16 +260,31 @@ func (b *Builder) writeArg(sb *strings.Builder, resArgs *[]any, verb byte, arg a
 	switch verb {
 	case '$':
 		b.counter++
-		sb.WriteByte('$')
-		sb.WriteString(strconv.Itoa(b.counter))
+		writePlaceholder(sb, verb, b.counter)
 		*resArgs = append(*resArgs, arg)
-	case '?':
-		sb.WriteByte('?')
+	case '@', ':':
+		// counter is incremented for named args too, to keep @pN matching the argument position.
+		b.counter++
+		if named, ok := arg.(sql.NamedArg); ok {
+			if !isArgName(named.Name) {
+				return fmt.Errorf("%w: %q", errInvalidArgName, named.Name)
+			}
+			sb.WriteByte(verb)
+			sb.WriteString(named.Name)
+		} else {
+			writePlaceholder(sb, verb, b.counter)
+			if b.namedArgs && verb == '@' {
+				arg = sql.Named("p"+strconv.Itoa(b.counter), arg)
+			}
+		}
 		*resArgs = append(*resArgs, arg)
-	case '@':
+	case '{':
 		b.counter++
-		sb.WriteString("@p")
-		sb.WriteString(strconv.Itoa(b.counter))
+		if err := b.writeTyped(sb, resArgs, arg); err != nil {
+			return err
+		}
+	case '?':
+		writePlaceholder(sb, verb, 0)
 		*resArgs = append(*resArgs, arg)
 	case 's':
 		isSimple = true
@@ -163,6 +308,7 @@ func (b *Builder) writeArg(sb *strings.Builder, resArgs *[]any, verb byte, arg a
 	if isSimple {
 		return nil
 	}
+	b.stats.Placeholders++
 
 	switch {
 	case b.placeholder == 0:
@@ -174,8 +320,42 @@ func (b *Builder) writeArg(sb *strings.Builder, resArgs *[]any, verb byte, arg a
 	return nil
 }
 
+// writePlaceholder writes n-th placeholder of the given style.
+func writePlaceholder(sb *strings.Builder, style byte, n int) {
+	switch style {
+	case '$':
+		sb.WriteByte('$')
+		sb.WriteString(strconv.Itoa(n))
+	case '?':
+		sb.WriteByte('?')
+	case '@':
+		sb.WriteString("@p")
+		sb.WriteString(strconv.Itoa(n))
+	case ':':
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(n))
+	}
+}
+
+// isArgName reports whether s is safe to be written as `@name`.
+func isArgName(s string) bool {
+	if s == "" || !isLetter(s[0]) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if s[i] != '_' && !isLetter(s[i]) && !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 func (b *Builder) writeDebug(sb *strings.Builder, arg any) {
 	switch arg := arg.(type) {
+	case sql.NamedArg:
+		b.writeDebug(sb, arg.Value)
+	case TypedArg:
+		b.writeDebug(sb, arg.Value)
 	case Columns:
 		sb.WriteString(arg.String())
 	case time.Time: